@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// command 子命令，限定可用的参数，帮助中只列出这些参数
+type command struct {
+	name     string
+	summary  string   // 总帮助中的一句话说明
+	args     string   // 帮助中参数部分的写法
+	flags    []string // 可用的参数，nil表示全部
+	hidden   []string // 仍然接受但不在帮助中列出的参数，兼容有子命令之前的写法
+	required []string // 必须指定的参数
+}
+
+// patternFlags 描述匹配模式的参数，generate、estimate和create2共用
+var patternFlags = []string{
+	"prefix", "suffix", "suffix-int", "divisible-by", "equals", "contains", "target", "distance", "min-upper", "match-hash", "match-hash-bytes",
+	"regex", "zeros", "zero-bytes", "palindrome", "charset", "charset-len", "charset-end",
+	"repeat", "repeat-end", "patterns-file", "checksum-match", "chain", "ignore-case",
+}
+
+// commonFlags 各子命令都可用的参数
+var commonFlags = []string{"config", "log-format", "log-level", "version"}
+
+// commands 全部子命令，没有指定子命令时为第一个generate
+var commands = []*command{
+	{
+		name:    "generate",
+		summary: "搜索匹配模式的地址（默认）",
+		args:    "[参数] [前缀]",
+		hidden:  []string{"estimate", "verify", "create2-deployer", "create2-init-hash", "shard"},
+	},
+	{
+		name:    "estimate",
+		summary: "估算模式的期望尝试次数和本机预计用时，不搜索",
+		args:    "[参数] [前缀]",
+		flags: slices.Concat(patternFlags, commonFlags, []string{
+			"workers", "limit-procs", "lock-threads", "mnemonic", "mnemonic-words", "hd-path", "hd-mnemonic-file",
+			"legacy-keygen", "seed", "create", "create-nonce", "create2-deployer", "create2-init-hash",
+		}),
+	},
+	{
+		name:    "verify",
+		summary: "检查私钥是否对应地址，退出码0为匹配、1为不匹配",
+		args:    "私钥:地址 | 私钥 地址",
+		flags:   []string{},
+	},
+	{
+		name:    "create2",
+		summary: "搜索CREATE2盐，使工厂合约部署的合约地址匹配模式",
+		args:    "-create2-deployer 地址 -create2-init-hash 哈希 [参数] [前缀]",
+		flags: slices.Concat(patternFlags, commonFlags, []string{
+			"create2-deployer", "create2-init-hash", "shard", "workers", "limit-procs", "lock-threads", "seed",
+			"count", "forever", "quiet", "unique", "unique-bloom", "progress-interval", "timeout", "best", "duration",
+			"count-only", "bench", "output", "output-per-match", "no-file", "format", "truncate", "gzip", "abi", "no-0x", "stream-json", "webhook", "metrics", "pprof",
+		}),
+		required: []string{"create2-deployer", "create2-init-hash"},
+	},
+}
+
+// parseCommand 从args（不含程序名）开头取出子命令，没有子命令时为generate，返回子命令和其余参数
+// 子命令名都不是十六进制，不会与位置参数形式的前缀混淆
+func parseCommand(args []string) (*command, []string) {
+	if len(args) > 0 {
+		for _, c := range commands {
+			if c.name == args[0] {
+				return c, args[1:]
+			}
+		}
+	}
+	return commands[0], args
+}
+
+// allows 判断子命令是否接受参数name
+func (c *command) allows(name string) bool {
+	return c.flags == nil || slices.Contains(c.flags, name)
+}
+
+// check 检查fs中已指定的参数（含 -config 设置的）都属于该子命令，且必需参数都已指定
+func (c *command) check(fs *flag.FlagSet) error {
+	var invalid []string
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+		if !c.allows(f.Name) {
+			invalid = append(invalid, "-"+f.Name)
+		}
+	})
+	if len(invalid) > 0 {
+		return fmt.Errorf("%s 子命令不支持 %s", c.name, strings.Join(invalid, " "))
+	}
+	for _, name := range c.required {
+		if !set[name] {
+			return fmt.Errorf("%s 子命令需要指定 -%s", c.name, name)
+		}
+	}
+	return nil
+}
+
+// usage 返回打印该子命令帮助的函数；generate同时列出全部子命令
+func (c *command) usage(fs *flag.FlagSet) func() {
+	return func() {
+		w := fs.Output()
+		if c == commands[0] {
+			fmt.Fprintf(w, "用法: %s [子命令] %s\n\n子命令:\n", fs.Name(), c.args)
+			for _, sub := range commands {
+				fmt.Fprintf(w, "  %-10s%s\n", sub.name, sub.summary)
+			}
+			fmt.Fprintf(w, "\n用 %s <子命令> -h 查看子命令的参数\n", fs.Name())
+		} else {
+			fmt.Fprintf(w, "用法: %s %s %s\n\n%s\n", fs.Name(), c.name, c.args, c.summary)
+		}
+		visible := flag.NewFlagSet(c.name, flag.ContinueOnError)
+		visible.SetOutput(w)
+		fs.VisitAll(func(f *flag.Flag) {
+			if c.allows(f.Name) && !slices.Contains(c.hidden, f.Name) {
+				visible.Var(f.Value, f.Name, f.Usage)
+				visible.Lookup(f.Name).DefValue = f.DefValue
+			}
+		})
+		var n int
+		visible.VisitAll(func(*flag.Flag) { n++ })
+		if n > 0 {
+			fmt.Fprintf(w, "\n%s 的参数:\n", c.name)
+			visible.PrintDefaults()
+		}
+	}
+}
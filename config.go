@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// applyConfig 读取JSON配置文件，键为命令行参数名（不含-），如 {"prefix": "dead", "workers": 4, "checksum-match": true}
+// 只设置命令行未显式指定的参数，即命令行优先；未知的键和不支持的值类型一并报错
+func applyConfig(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		return fmt.Errorf("解析失败: %w", err)
+	}
+
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var unknown []string
+	for _, k := range keys {
+		if k == "config" || fs.Lookup(k) == nil {
+			unknown = append(unknown, k)
+			continue
+		}
+		var s string
+		switch v := values[k].(type) {
+		case string:
+			s = v
+		case json.Number:
+			s = v.String()
+		case bool:
+			s = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("%s: 只支持字符串、数字和布尔值", k)
+		}
+		if explicit[k] {
+			continue
+		}
+		if err := fs.Set(k, s); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	if len(unknown) > 0 {
+		return errors.New("未知的配置项: " + strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+)
+
+// startPprof 在addr上启动pprof调试服务，只注册到独立的mux，不暴露在DefaultServeMux上
+func startPprof(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	bound, err := serveHTTP(addr, "pprof", mux)
+	if err != nil {
+		return err
+	}
+	slog.Info("pprof已启动", "url", fmt.Sprintf("http://%s/debug/pprof/", bound))
+	return nil
+}
+
+// serveHTTP 在addr上后台运行HTTP服务并返回实际监听的地址
+// 先同步监听以便端口被占用时立即报错
+func serveHTTP(addr, name string, h http.Handler) (net.Addr, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := http.Serve(ln, h); err != nil {
+			slog.Error(name+"服务退出", "err", err)
+		}
+	}()
+	return ln.Addr(), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+)
+
+// dedup 记录已输出的地址，-unique 时跳过重复的匹配
+type dedup interface {
+	// seen 返回address是否已经出现过，并记录address
+	seen(address string) bool
+}
+
+// exactSet 精确去重，内存随匹配数线性增长，每个地址约100字节
+type exactSet map[string]struct{}
+
+func (s exactSet) seen(address string) bool {
+	if _, ok := s[address]; ok {
+		return true
+	}
+	s[address] = struct{}{}
+	return false
+}
+
+// bloomFalsePositive 布隆过滤器按预计数量设计的误判率
+const bloomFalsePositive = 0.01
+
+// bloomFilter 布隆过滤器，内存固定，但有小概率把新地址误判为重复而跳过，不会漏判真正的重复
+type bloomFilter struct {
+	bits []uint64
+	k    int
+}
+
+// newBloomFilter 创建容纳n个地址、误判率约为bloomFalsePositive的布隆过滤器
+// 位数 m = -n·ln(p)/(ln2)²，哈希个数 k = m/n·ln2
+func newBloomFilter(n int) *bloomFilter {
+	m := math.Ceil(-float64(n) * math.Log(bloomFalsePositive) / (math.Ln2 * math.Ln2))
+	k := max(1, int(math.Round(m/float64(n)*math.Ln2)))
+	return &bloomFilter{bits: make([]uint64, (int(m)+63)/64), k: k}
+}
+
+func (b *bloomFilter) seen(address string) bool {
+	// 地址本身是哈希输出，直接取其中两段做双重哈希
+	raw, _ := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+	if len(raw) < 16 {
+		return false
+	}
+	h1, h2 := binary.BigEndian.Uint64(raw), binary.BigEndian.Uint64(raw[8:])|1
+	n := uint64(len(b.bits)) * 64
+	found := true
+	for i := 0; i < b.k; i++ {
+		bit := (h1 + uint64(i)*h2) % n
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			found = false
+			b.bits[bit/64] |= 1 << (bit % 64)
+		}
+	}
+	return found
+}
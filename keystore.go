@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/dmqf12/ethaddress/vanity"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// passwordEnv 未指定 -password 时读取keystore密码的环境变量
+const passwordEnv = "ETHADDRESS_PASSWORD"
+
+// keystoreExporter 将匹配的私钥导出为加密的keystore v3文件（scrypt + AES-128-CTR）
+// 文件名与Geth相同，为 UTC--<时间>--<地址>
+type keystoreExporter struct {
+	ks       *keystore.KeyStore
+	password string
+}
+
+// newKeystoreExporter 创建导出到dir的keystore导出器
+func newKeystoreExporter(dir, password string) *keystoreExporter {
+	return &keystoreExporter{
+		ks:       keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP),
+		password: password,
+	}
+}
+
+// export 导出一个匹配并返回文件路径，CREATE2模式没有私钥时跳过，跳过或失败时返回空字符串
+func (e *keystoreExporter) export(m vanity.Match) string {
+	if m.PrivateKey == "" {
+		return ""
+	}
+	priv, err := crypto.HexToECDSA(m.PrivateKey)
+	if err != nil {
+		slog.Error("解析私钥失败", "address", m.Address, "err", err)
+		return ""
+	}
+	account, err := e.ks.ImportECDSA(priv, e.password)
+	if err != nil {
+		slog.Error("写入keystore失败", "address", m.Address, "err", err)
+		return ""
+	}
+	slog.Info("已导出keystore", "address", m.Address, "path", account.URL.Path)
+	return account.URL.Path
+}
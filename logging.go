@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// levelSecret 记录私钥、助记词等敏感字段的日志级别，低于debug，只有 -log-level secret 时才输出
+const levelSecret = slog.LevelDebug - 4
+
+// parseLogLevel 解析 -log-level
+func parseLogLevel(s string) (slog.Level, error) {
+	if s == "secret" {
+		return levelSecret, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("未知的日志级别 %q", s)
+	}
+	return level, nil
+}
+
+// newLogger 创建写到w的日志，format为text或json
+func newLogger(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && a.Value.Any() == levelSecret {
+				a.Value = slog.StringValue("SECRET")
+			}
+			return a
+		},
+	}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("未知的日志格式 %q", format)
+}
+
+// logMatch 在info级别记录匹配的公开信息，私钥和助记词只在secret级别单独记录
+func logMatch(m vanity.Match) {
+	attrs := []any{"address", m.ChecksumAddress, "attempts", m.Attempts}
+	if m.Sender != "" {
+		attrs = append(attrs, "sender", m.Sender)
+	}
+	if m.Salt != "" {
+		attrs = append(attrs, "salt", m.Salt)
+	}
+	if m.Path != "" {
+		attrs = append(attrs, "path", m.Path)
+	}
+	if m.ContainsAt >= 0 {
+		attrs = append(attrs, "position", m.ContainsAt)
+	}
+	if m.Distance >= 0 {
+		attrs = append(attrs, "distance", m.Distance)
+	}
+	if m.Uppercase > 0 {
+		attrs = append(attrs, "uppercase", m.Uppercase)
+	}
+	slog.Info("找到匹配", attrs...)
+
+	if m.PrivateKey == "" {
+		return
+	}
+	secret := []any{"address", m.ChecksumAddress, "privateKey", m.PrivateKey}
+	if m.Mnemonic != "" {
+		secret = append(secret, "mnemonic", m.Mnemonic)
+	}
+	slog.Log(context.Background(), levelSecret, "匹配私钥", secret...)
+}
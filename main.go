@@ -0,0 +1,1072 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// printStats 打印统计信息，out非nil时同时记录到文件
+// quiet时只打印一行"校验和地址 私钥"（CREATE2模式为盐），结果写到标准输出时不再重复打印；abi时附带32字节补零形式
+func printStats(start time.Time, m vanity.Match, out *resultLog, quiet, abi bool) {
+	var padded string
+	if abi {
+		padded, _ = vanity.AddressTo32Bytes(m.Address)
+		// -no-0x 时地址不带0x，补零形式也不带
+		if !strings.HasPrefix(m.Address, "0x") {
+			padded = padded[2:]
+		}
+	}
+	elapsed := time.Since(start).Seconds()
+	if quiet {
+		if out == nil || out.path != stdoutPath {
+			secret := m.PrivateKey
+			if m.Salt != "" {
+				secret = m.Salt
+			}
+			address := m.ChecksumAddress
+			if m.Tron != "" {
+				address = m.Tron
+			} else if m.ICAP != "" {
+				address = m.ICAP
+			}
+			if abi {
+				fmt.Println(address, secret, padded)
+			} else {
+				fmt.Println(address, secret)
+			}
+		}
+		if out != nil {
+			out.logResult(m, elapsed)
+		}
+		return
+	}
+	fmt.Printf("用时: %.2f秒\n", elapsed)
+	fmt.Printf("总地址数: %d\n", m.Attempts)
+	fmt.Printf("速度: %.2f 地址/秒\n", float64(m.Attempts)/elapsed)
+	fmt.Printf("地址: %s\n", m.Address)
+	fmt.Printf("校验和地址: %s\n", m.ChecksumAddress)
+	if abi {
+		fmt.Printf("ABI编码(32字节): %s\n", padded)
+	}
+	if m.Tron != "" {
+		fmt.Printf("Tron地址: %s\n", m.Tron)
+	}
+	if m.ICAP != "" {
+		fmt.Printf("ICAP地址: %s\n", m.ICAP)
+	}
+	if m.Sender != "" {
+		fmt.Printf("部署账户: %s\n", m.Sender)
+	}
+	if m.Salt != "" {
+		fmt.Printf("盐: %s\n", m.Salt)
+	} else {
+		fmt.Printf("私钥: %s\n", m.PrivateKey)
+	}
+	if m.Mnemonic != "" {
+		fmt.Printf("助记词: %s\n", m.Mnemonic)
+	}
+	if m.Path != "" {
+		fmt.Printf("派生路径: %s\n", m.Path)
+	}
+	if m.PublicKey != "" {
+		fmt.Printf("公钥: %s\n", m.PublicKey)
+		fmt.Printf("压缩公钥: %s\n", m.CompressedKey)
+	}
+	fmt.Printf("前导零: %d\n", m.LeadingZeros)
+	if m.RepeatRun > 0 {
+		fmt.Printf("连续相同字符: %d\n", m.RepeatRun)
+	}
+	if m.MatchedPattern != "" {
+		fmt.Printf("命中模式: %s\n", m.MatchedPattern)
+	}
+	if m.ContainsAt >= 0 {
+		fmt.Printf("子串位置: %d\n", m.ContainsAt)
+	}
+	if m.Distance >= 0 {
+		fmt.Printf("与目标距离: %d\n", m.Distance)
+	}
+	if m.Uppercase > 0 {
+		fmt.Printf("大写字母: %d\n", m.Uppercase)
+	}
+
+	if out != nil {
+		out.logResult(m, elapsed)
+	}
+}
+
+// prefixBody 返回用于匹配的前缀：地址显示时带0x，照此输入的以太坊前缀去掉0x（或0X），
+// 模式匹配的是不含0x的40位十六进制；Tron、ICAP地址本身不以0x开头，原样返回
+func prefixBody(prefix, chain string) string {
+	if chain != "ethereum" {
+		return prefix
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(prefix, "0x"), "0X")
+}
+
+// trimHexPrefix 去掉结果中地址、部署账户、盐和公钥的0x前缀（-no-0x），私钥本来就不带0x
+func trimHexPrefix(m vanity.Match) vanity.Match {
+	for _, s := range []*string{&m.Address, &m.ChecksumAddress, &m.Sender, &m.Salt, &m.PublicKey, &m.CompressedKey} {
+		*s = strings.TrimPrefix(*s, "0x")
+	}
+	return m
+}
+
+// logWorkerStats 记录每个worker的尝试次数及占总数的比例，用于发现被饿死或调度不均的worker
+func logWorkerStats(counts []int64, total int64) {
+	for i, n := range counts {
+		share := 0.0
+		if total > 0 {
+			share = float64(n) / float64(total) * 100
+		}
+		slog.Info("worker统计", "worker", i, "attempts", n, "share", fmt.Sprintf("%.1f%%", share))
+	}
+}
+
+// exitTimeout -timeout 到期仍未找到足够匹配时的退出码，与出错（1）和参数错误（2）区分
+const exitTimeout = 3
+
+// estimateSamples 正则等模式蒙特卡洛估算的采样次数
+const estimateSamples = 200000
+
+// warmupDuration 启动时测量本机速度的预热时长
+const warmupDuration = 500 * time.Millisecond
+
+// printEstimate 打印模式的期望尝试次数，并按本机1秒的生成速度估算用时
+func printEstimate(p vanity.Pattern, opts vanity.Options) {
+	d := p.Difficulty()
+	if d == 0 {
+		var hits int
+		d, hits = vanity.SampleDifficulty(p, estimateSamples)
+		if hits == 0 {
+			fmt.Printf("采样 %d 次未命中，期望尝试次数大于 %d\n", estimateSamples, estimateSamples)
+			return
+		}
+		fmt.Printf("采样 %d 次命中 %d 次\n", estimateSamples, hits)
+	}
+	fmt.Printf("期望尝试次数: %.0f\n", d)
+
+	rate := vanity.MeasureRate(context.Background(), opts, time.Second)
+	fmt.Printf("本机速度: %.2f 地址/秒\n", rate)
+	printETA(d, rate)
+}
+
+// logStartupEstimate 搜索开始前记录期望尝试次数，并用短暂预热测得的速度估算用时，无法直接计算难度时不记录
+func logStartupEstimate(p vanity.Pattern, opts vanity.Options) {
+	d := p.Difficulty()
+	if d == 0 {
+		return
+	}
+	opts.OnProgress = nil
+	rate := vanity.MeasureRate(context.Background(), opts, warmupDuration)
+	mean, p50, p90 := etaQuantiles(d, rate)
+	slog.Info("预计用时（实际用时是随机的）", "attempts", fmt.Sprintf("%.0f", d), "rate", fmt.Sprintf("%.0f", rate),
+		"mean", mean, "p50", p50, "p90", p90)
+}
+
+// printETA 按速度rate打印期望用时及50%、90%概率找到的用时
+func printETA(d, rate float64) {
+	mean, p50, p90 := etaQuantiles(d, rate)
+	fmt.Printf("预计用时: 平均 %s，50%%概率 %s 内，90%%概率 %s 内（按 %.0f 地址/秒，实际用时是随机的）\n",
+		mean, p50, p90, rate)
+}
+
+// etaQuantiles 返回按速度rate的平均用时及50%、90%概率找到的用时
+func etaQuantiles(d, rate float64) (mean, p50, p90 string) {
+	eta := func(attempts float64) string { return formatETA(attempts / rate) }
+	return eta(d), eta(vanity.AttemptsForProbability(d, 0.5)), eta(vanity.AttemptsForProbability(d, 0.9))
+}
+
+// maxETASeconds time.Duration能表示的最大秒数，约292年
+const maxETASeconds = float64(math.MaxInt64) / float64(time.Second)
+
+// secondsPerYear 按365.25天计的一年秒数
+const secondsPerYear = 365.25 * 24 * 3600
+
+// formatETA 把秒数格式化为用时，超出time.Duration范围时改用年数，避免换算溢出成负数
+func formatETA(seconds float64) string {
+	if seconds >= maxETASeconds {
+		return fmt.Sprintf("约%.3g年", seconds/secondsPerYear)
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
+
+// progressETA 按难度d和当前速度rate估算找到剩余remaining个匹配的平均用时，forever时只估算下一个
+// 每次尝试独立，已经搜索的时长不影响剩余用时；难度无法计算（如正则）或速度未知时返回"未知"
+func progressETA(d, rate float64, remaining int64, forever bool) string {
+	if forever {
+		remaining = 1
+	}
+	if d == 0 || rate <= 0 || remaining <= 0 {
+		return "未知"
+	}
+	return formatETA(float64(remaining) * d / rate)
+}
+
+// runBench 运行基准测试并打印总地址数和速度，可用Ctrl-C提前结束
+func runBench(opts vanity.Options, d time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("基准测试 %s...\n", d)
+	count, elapsed := vanity.Benchmark(ctx, opts, d)
+	fmt.Printf("总地址数: %d\n", count)
+	fmt.Printf("用时: %.2f秒\n", elapsed.Seconds())
+	fmt.Printf("速度: %.2f 地址/秒\n", float64(count)/elapsed.Seconds())
+}
+
+// runCountOnly 生成n个地址，打印匹配p的个数及实测与估算的期望尝试次数，私钥用后即清零，可用Ctrl-C提前结束
+func runCountOnly(opts vanity.Options, p vanity.Pattern, n int64) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("生成 %d 个地址并统计匹配 %s 的个数...\n", n, p)
+	g := vanity.NewGenerator(opts)
+	matched, err := g.CountMatches(ctx, p, n)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "生成失败:", err)
+		return 1
+	}
+	attempts := g.Count()
+	fmt.Printf("尝试: %d，匹配: %d\n", attempts, matched)
+	if matched > 0 {
+		fmt.Printf("实测期望尝试次数: %.2f\n", float64(attempts)/float64(matched))
+	} else {
+		fmt.Printf("未命中，期望尝试次数大于 %d\n", attempts)
+	}
+	if d := p.Difficulty(); d > 0 {
+		fmt.Printf("估算期望尝试次数: %.2f\n", d)
+	}
+	return 0
+}
+
+// chiSquareCritical 自由度15的卡方分布在0.1%显著性水平的临界值
+const chiSquareCritical = 37.70
+
+// runDistribution 生成n个地址，打印各首位十六进制字符的频率和卡方检验结果，可用Ctrl-C提前结束
+// 返回退出码：分布与均匀分布无显著差异为0，有显著偏差为1
+func runDistribution(opts vanity.Options, n int64) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("生成 %d 个地址并统计首位字符...\n", n)
+	counts, err := vanity.NewGenerator(opts).Distribution(ctx, n)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "生成失败:", err)
+		return 1
+	}
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 1
+	}
+	for d, c := range counts {
+		fmt.Printf("%x: %d (%.2f%%)\n", d, c, float64(c)/float64(total)*100)
+	}
+	chi := vanity.ChiSquare(counts[:])
+	fmt.Printf("总数: %d，期望每个 %.2f%%，卡方 = %.2f（自由度15，0.1%%临界值 %.2f）\n", total, 100.0/16, chi, chiSquareCritical)
+	if chi > chiSquareCritical {
+		fmt.Println("分布明显不均匀，生成路径可能有问题")
+		return 1
+	}
+	fmt.Println("分布与均匀分布无显著差异")
+	return 0
+}
+
+// runVerify 检查"私钥:地址"中的私钥是否对应该地址，返回退出码：0匹配，1不匹配，2输入无效
+func runVerify(s string) int {
+	privHex, address, ok := strings.Cut(s, ":")
+	if !ok {
+		fmt.Fprintln(os.Stderr, "格式应为 私钥:地址")
+		return 2
+	}
+	match, actual, err := vanity.VerifyKey(privHex, address)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "无效的私钥或地址:", err)
+		return 2
+	}
+	if !match {
+		fmt.Printf("不匹配: 私钥对应的地址为 %s\n", vanity.ToChecksumAddress(actual))
+		return 1
+	}
+	fmt.Printf("匹配: %s\n", vanity.ToChecksumAddress(actual))
+	return 0
+}
+
+// runImport 读取path中每行一个的十六进制私钥，打印"地址 校验和地址"，p非空时只打印匹配的地址
+// 空行和#开头的行忽略，无效的私钥报告到stderr后继续，返回退出码
+func runImport(path string, p vanity.Pattern) int {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "无法读取私钥列表:", err)
+		return 1
+	}
+	defer f.Close()
+
+	filter := p.Validate() == nil
+	invalid := 0
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		priv, err := vanity.ParsePrivateKey(text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "第%d行: %v\n", line, err)
+			invalid++
+			continue
+		}
+		address := vanity.PrivateKeyToAddress(priv)
+		if filter && !p.Match(address) {
+			continue
+		}
+		fmt.Println(address, vanity.ToChecksumAddress(address))
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "读取私钥列表失败:", err)
+		return 1
+	}
+	if invalid > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runCheckChecksums 读取path中每行一个的地址，输出CSV行"地址,valid"，地址按原样输出
+// valid为true表示地址与其EIP-55校验和形式完全一致；全小写、全大写（未带校验和）、校验和错误或格式无效都为false
+// 空行和#开头的行忽略，全部为true时退出码为0，否则为1
+func runCheckChecksums(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "无法读取地址列表:", err)
+		return 1
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"address", "valid"})
+	allValid := true
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		valid := strings.HasPrefix(text, "0x") && vanity.ToChecksumAddress(text) == text
+		allValid = allValid && valid
+		w.Write([]string{text, strconv.FormatBool(valid)})
+	}
+	w.Flush()
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "读取地址列表失败:", err)
+		return 1
+	}
+	if err := w.Error(); err != nil {
+		fmt.Fprintln(os.Stderr, "输出失败:", err)
+		return 1
+	}
+	if !allValid {
+		return 1
+	}
+	return 0
+}
+
+// loadPatternSet 读取 -patterns-file 指定的模式列表
+func loadPatternSet(path string) (*vanity.PatternSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return vanity.ParsePatternSet(f)
+}
+
+// loadHDIndex 读取path中的助记词，在hdPath去掉最后一级后的父路径下按索引搜索
+func loadHDIndex(path, hdPath string) (*vanity.HDIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(data)
+	parent, err := vanity.ParsePath(hdPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(parent) == 0 {
+		return nil, errors.New("-hd-path 至少需要一级")
+	}
+	return vanity.NewHDIndex(strings.Join(strings.Fields(string(data)), " "), parent[:len(parent)-1])
+}
+
+// parseCreate2 解析CREATE2部署者地址和初始化代码哈希
+func parseCreate2(deployer, initCodeHash string) (*vanity.Create2, error) {
+	var c vanity.Create2
+	deployer, err := vanity.Normalize(deployer)
+	if err != nil {
+		return nil, fmt.Errorf("部署者地址: %w", err)
+	}
+	if err := decodeHex(c.Deployer[:], deployer); err != nil {
+		return nil, fmt.Errorf("部署者地址: %w", err)
+	}
+	if err := decodeHex(c.InitCodeHash[:], initCodeHash); err != nil {
+		return nil, fmt.Errorf("初始化代码哈希: %w", err)
+	}
+	return &c, nil
+}
+
+// parseShard 解析 i/n 形式的分片参数，要求 0 <= i < n
+func parseShard(s string) (i, n uint64, err error) {
+	is, ns, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, 0, errors.New("格式应为 i/n")
+	}
+	if i, err = strconv.ParseUint(is, 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if n, err = strconv.ParseUint(ns, 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if n == 0 || i >= n {
+		return 0, 0, errors.New("需要 0 <= i < n")
+	}
+	return i, n, nil
+}
+
+// decodeHex 将可带0x前缀的十六进制解码到dst，长度必须恰好为len(dst)字节
+func decodeHex(dst []byte, s string) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("需要%d字节，实际%d字节", len(dst), len(b))
+	}
+	copy(dst, b)
+	return nil
+}
+
+func main() {
+	prefix := flag.String("prefix", "", "地址前缀模式（可带0x），?匹配任意一个字符，如 -prefix dead、-prefix 0xdead、-prefix ab??cd")
+	suffix := flag.String("suffix", "", "地址后缀模式，?匹配任意一个字符，如 -suffix beef")
+	suffixInt := flag.Int("suffix-int", 0, "把地址末尾N个十六进制字符当作整数，配合 -divisible-by 或 -equals 匹配")
+	divisibleBy := flag.Uint64("divisible-by", 0, "-suffix-int 的整数须能被该值整除，如 -suffix-int 4 -divisible-by 1000")
+	equals := flag.String("equals", "", "-suffix-int 的整数须等于该十进制值")
+	contains := flag.String("contains", "", "地址任意位置包含的十六进制子串，如 -contains c0ffee")
+	targetAddr := flag.String("target", "", "近似匹配的目标地址，与 -distance 一起使用")
+	distance := flag.Int("distance", 0, "与 -target 最多相差的半字节数（汉明距离）")
+	minUpper := flag.Int("min-upper", 0, "EIP-55校验和地址中至少N个大写字母")
+	matchHash := flag.String("match-hash", "", "匹配开头与keccak256(LABEL)前 -match-hash-bytes 个字节相同的地址，如 -match-hash vitalik")
+	matchHashBytes := flag.Int("match-hash-bytes", 2, "-match-hash 比较的字节数，1到20")
+	regex := flag.String("regex", "", "正则模式，匹配不含0x的40位小写十六进制地址")
+	zeros := flag.Int("zeros", 0, "至少N个前导零半字节")
+	zeroBytes := flag.Int("zero-bytes", 0, "至少N个前导零字节")
+	palindrome := flag.Int("palindrome", 0, "首尾各K个字符互为镜像，20为整个地址回文")
+	charset := flag.String("charset", "", "只允许这些十六进制字符，如 -charset 0123456789")
+	charsetLen := flag.Int("charset-len", 0, "字符集约束开头N个字符，0为整个地址")
+	charsetEnd := flag.Bool("charset-end", false, "字符集约束末尾而不是开头的N个字符")
+	repeat := flag.Int("repeat", 0, "开头至少N个连续相同字符")
+	repeatEnd := flag.Bool("repeat-end", false, "-repeat 约束末尾而不是开头")
+	patternsFile := flag.String("patterns-file", "", "模式列表文件，每行一个前缀（*开头为后缀），命中任意一个即可")
+	checksumMatch := flag.Bool("checksum-match", false, "按EIP-55校验和地址区分大小写匹配")
+	chain := flag.String("chain", "ethereum", "地址格式: ethereum、tron（base58check，以T开头）或 icap（以XE开头），模式匹配该格式的地址")
+	ignoreCase := flag.Bool("ignore-case", false, "忽略大小写匹配，优先于 -checksum-match")
+	workers := flag.Int("workers", runtime.NumCPU(), "worker数量，默认为CPU核心数")
+	limitProcs := flag.Bool("limit-procs", false, "将GOMAXPROCS降到 -workers，与其他负载共用机器时限制CPU占用")
+	lockThreads := flag.Bool("lock-threads", false, "每个worker固定在独占的系统线程上（runtime.LockOSThread）")
+	target := flag.Int64("count", 1, "需要找到的匹配数量")
+	quiet := flag.Bool("quiet", false, "只输出匹配的地址和私钥，不打印启动信息、进度和统计，错误仍输出到stderr")
+	forever := flag.Bool("forever", false, "忽略 -count，持续搜索并记录每个匹配，直到收到中断信号")
+	unique := flag.Bool("unique", false, "跳过已输出过的地址（重复极为罕见），默认用精确集合")
+	uniqueBloom := flag.Int("unique-bloom", 0, "-unique 改用按N个地址设计的布隆过滤器，内存固定但约1%概率误跳过新地址")
+	mnemonic := flag.Bool("mnemonic", false, "助记词模式：私钥由随机BIP-39助记词派生")
+	mnemonicWords := flag.Int("mnemonic-words", 12, "助记词模式：助记词个数，12或24")
+	hdPath := flag.String("hd-path", vanity.DefaultPath, "助记词模式：BIP-32派生路径")
+	hdMnemonicFile := flag.String("hd-mnemonic-file", "", "在该文件中已有助记词的派生空间中搜索，依次尝试 -hd-path 最后一级的索引")
+	progressInterval := flag.Duration("progress-interval", 5*time.Second, "进度输出到stderr的间隔，0为关闭")
+	no0x := flag.Bool("no-0x", false, "输出（控制台和结果文件）中的地址、盐和公钥不带0x前缀")
+	abi := flag.Bool("abi", false, "同时输出地址左补零到32字节的形式（ABI编码、事件topic中的形式）")
+	withPubkey := flag.Bool("with-pubkey", false, "同时输出未压缩和压缩公钥")
+	streamJSON := flag.Bool("stream-json", false, "标准输出只输出每行一个JSON的事件流（type为progress或match），供前端实时读取")
+	webhookURL := flag.String("webhook", "", "每个匹配以JSON POST到该URL（含私钥，应使用HTTPS）")
+	pprofAddr := flag.String("pprof", "", "在该地址启动pprof调试服务，如 -pprof localhost:6060，默认不启动")
+	metricsAddr := flag.String("metrics", "", "在该地址以Prometheus格式提供/metrics，如 -metrics :9100，默认不启动")
+	seed := flag.String("seed", "", "用固定种子生成可复现的私钥序列，仅用于测试，生成的私钥不安全")
+	legacyKeygen := flag.Bool("legacy-keygen", false, "用ecdsa.GenerateKey生成私钥（较慢，用于对照）")
+	verify := flag.String("verify", "", "检查私钥是否对应地址，格式为 私钥:地址，退出码0为匹配、1为不匹配")
+	checkChecksums := flag.String("check-checksums", "", "读取每行一个地址的文件，输出\"地址,true|false\"表示是否带有正确的EIP-55校验和")
+	importFile := flag.String("import", "", "读取每行一个十六进制私钥的文件，计算并打印地址，指定模式时只打印匹配的")
+	best := flag.Int("best", 0, "不匹配模式，搜索 -duration 时长后输出前导零最多的K个地址")
+	duration := flag.Duration("duration", 0, "-best 模式的搜索时长，0为直到Ctrl-C")
+	timeout := flag.Duration("timeout", 0, "搜索超过该时长仍未找到足够匹配时放弃，退出码为3，0为不限")
+	countOnly := flag.Int64("count-only", 0, "实测模式难度：生成N个地址，只统计匹配的个数，不输出也不记录私钥")
+	distribution := flag.Int64("distribution", 0, "自检：生成N个地址（不匹配模式），统计各首位十六进制字符的频率")
+	bench := flag.Duration("bench", 0, "基准测试：不匹配模式，运行给定时长后输出速度")
+	estimate := flag.Bool("estimate", false, "只估算难度和预计用时，不开始搜索")
+	keystoreDir := flag.String("keystore", "", "将匹配的私钥导出为keystore v3文件到该目录")
+	qrDir := flag.String("qr", "", "为匹配的地址生成二维码PNG到该目录，-为打印到终端")
+	qrKeystore := flag.Bool("qr-keystore", false, "配合 -keystore 同时为keystore JSON生成二维码")
+	password := flag.String("password", "", "keystore密码，未指定时读取环境变量"+passwordEnv)
+	output := flag.String("output", defaultOutput, "结果文件路径，不存在的目录会自动创建，-为标准输出")
+	outputPerMatch := flag.String("output-per-match", "", "每个匹配写到该目录下以地址命名的单独文件，代替 -output 的单个文件")
+	noFile := flag.Bool("no-file", false, "不记录结果文件，只打印到控制台")
+	format := flag.String("format", "text", "结果文件格式: text、json 或 csv")
+	truncate := flag.Bool("truncate", false, "每次运行先清空结果文件，默认追加")
+	gzipOutput := flag.Bool("gzip", false, "结果文件经gzip压缩写入，文件名自动加.gz，可用gunzip读取")
+	deployer := flag.String("create2-deployer", "", "CREATE2模式：部署者（工厂合约）地址")
+	initCodeHash := flag.String("create2-init-hash", "", "CREATE2模式：合约初始化代码的keccak256")
+	shard := flag.String("shard", "", "CREATE2模式：多机分片 i/n，只尝试 salt%n==i 的盐，如 -shard 0/4")
+	create := flag.Bool("create", false, "CREATE模式：匹配新账户部署的合约地址")
+	createNonce := flag.Uint64("create-nonce", 0, "CREATE模式：部署交易的nonce")
+	logFormat := flag.String("log-format", "text", "stderr日志格式: text 或 json")
+	configFile := flag.String("config", "", "从JSON配置文件读取参数，键为参数名（如 \"prefix\"），命令行指定的参数优先")
+	showVersion := flag.Bool("version", false, "打印版本和构建信息后退出")
+	logLevel := flag.String("log-level", "info", "日志级别: debug、info、warn、error，secret时日志中也记录私钥")
+	cmd, args := parseCommand(os.Args[1:])
+	flag.Usage = cmd.usage(flag.CommandLine)
+	flag.CommandLine.Parse(args)
+
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+	if cmd.name == "verify" {
+		if err := cmd.check(flag.CommandLine); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		switch flag.NArg() {
+		case 1:
+			os.Exit(runVerify(flag.Arg(0)))
+		case 2:
+			os.Exit(runVerify(flag.Arg(0) + ":" + flag.Arg(1)))
+		}
+		flag.Usage()
+		os.Exit(2)
+	}
+	// 位置参数作为前缀，如 ethaddress dead
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		if *prefix != "" {
+			fmt.Fprintln(os.Stderr, "位置参数已作为前缀，不能再指定 -prefix")
+			os.Exit(2)
+		}
+		// 通过flag.Set设置，使 -config 视其为命令行已指定的参数
+		flag.Set("prefix", flag.Arg(0))
+	default:
+		fmt.Fprintln(os.Stderr, "最多只能有一个位置参数（前缀），其余参数须放在它之前:", strings.Join(flag.Args()[1:], " "))
+		os.Exit(2)
+	}
+	if *configFile != "" {
+		if err := applyConfig(flag.CommandLine, *configFile); err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -config:", err)
+			os.Exit(2)
+		}
+	}
+	if err := cmd.check(flag.CommandLine); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintf(os.Stderr, "用 %s %s -h 查看可用的参数\n", flag.CommandLine.Name(), cmd.name)
+		os.Exit(2)
+	}
+	if cmd.name == "estimate" {
+		flag.Set("estimate", "true")
+	}
+
+	level, err := parseLogLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// -quiet 时只保留警告和错误
+	if *quiet {
+		level = max(level, slog.LevelWarn)
+	}
+	logger, err := newLogger(os.Stderr, *logFormat, level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	if *workers < 1 {
+		fmt.Fprintln(os.Stderr, "-workers 必须 >= 1")
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" && *format != "csv" {
+		fmt.Fprintln(os.Stderr, "未知的 -format:", *format)
+		os.Exit(2)
+	}
+	if *target < 1 {
+		fmt.Fprintln(os.Stderr, "-count 必须 >= 1")
+		os.Exit(2)
+	}
+
+	pattern := vanity.Pattern{
+		Prefix:     prefixBody(*prefix, *chain),
+		Suffix:     *suffix,
+		Contains:   *contains,
+		Distance:   *distance,
+		MinUpper:   *minUpper,
+		Zeros:      *zeros,
+		ZeroBytes:  *zeroBytes,
+		Palindrome: *palindrome,
+		Charset:    *charset,
+		CharsetLen: *charsetLen,
+		CharsetEnd: *charsetEnd,
+		Repeat:     *repeat,
+		RepeatEnd:  *repeatEnd,
+		Checksum:   *checksumMatch,
+		IgnoreCase: *ignoreCase,
+		Tron:       *chain == "tron",
+		ICAP:       *chain == "icap",
+	}
+	if *chain != "ethereum" && *chain != "tron" && *chain != "icap" {
+		fmt.Fprintln(os.Stderr, "未知的 -chain:", *chain)
+		os.Exit(2)
+	}
+	if *matchHash != "" {
+		if *prefix != "" {
+			fmt.Fprintln(os.Stderr, "-match-hash 由标签哈希计算前缀，不能与 -prefix 同时使用")
+			os.Exit(2)
+		}
+		hashPrefix, err := vanity.LabelHashPrefix(*matchHash, *matchHashBytes)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -match-hash-bytes:", err)
+			os.Exit(2)
+		}
+		pattern.Prefix = hashPrefix
+		slog.Info("由标签哈希计算前缀", "label", *matchHash, "prefix", hashPrefix)
+	}
+	if *suffixInt > 0 {
+		pattern.SuffixInt = &vanity.SuffixInt{Digits: *suffixInt, DivisibleBy: *divisibleBy}
+		if *equals != "" {
+			v, err := strconv.ParseUint(*equals, 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "无效的 -equals:", err)
+				os.Exit(2)
+			}
+			pattern.SuffixInt.Equals = &v
+		}
+	} else if *divisibleBy != 0 || *equals != "" {
+		fmt.Fprintln(os.Stderr, "-divisible-by 和 -equals 需要同时指定 -suffix-int")
+		os.Exit(2)
+	}
+	if *targetAddr != "" {
+		addr, err := vanity.Normalize(*targetAddr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -target:", err)
+			os.Exit(2)
+		}
+		pattern.Target = addr[2:]
+	}
+	if *regex != "" {
+		re, err := regexp.Compile(*regex)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的正则:", err)
+			os.Exit(2)
+		}
+		pattern.Regex = re
+	}
+	if *patternsFile != "" {
+		set, err := loadPatternSet(*patternsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无法读取模式列表:", err)
+			os.Exit(2)
+		}
+		pattern.Any = set
+	}
+	if *verify != "" {
+		os.Exit(runVerify(*verify))
+	}
+	if *checkChecksums != "" {
+		os.Exit(runCheckChecksums(*checkChecksums))
+	}
+
+	if *best < 0 {
+		fmt.Fprintln(os.Stderr, "-best 必须 >= 1")
+		os.Exit(2)
+	}
+	if *best > 0 && pattern.Validate() == nil {
+		fmt.Fprintln(os.Stderr, "-best 按前导零个数打分，不能与匹配模式同时使用")
+		os.Exit(2)
+	}
+	if *uniqueBloom < 0 || (*uniqueBloom > 0 && !*unique) {
+		fmt.Fprintln(os.Stderr, "-unique-bloom 需要同时指定 -unique，且不能为负")
+		os.Exit(2)
+	}
+	if *timeout < 0 {
+		fmt.Fprintln(os.Stderr, "-timeout 不能为负")
+		os.Exit(2)
+	}
+	if *timeout > 0 && *best > 0 {
+		fmt.Fprintln(os.Stderr, "-best 模式用 -duration 限定时长，不能与 -timeout 同时使用")
+		os.Exit(2)
+	}
+	if *duration > 0 && *best == 0 {
+		fmt.Fprintln(os.Stderr, "-duration 只适用于 -best 模式")
+		os.Exit(2)
+	}
+
+	// 基准测试、分布自检和 -best 不需要模式，导入私钥时模式只用于过滤
+	if err := pattern.Validate(); err != nil && !(errors.Is(err, vanity.ErrEmptyPattern) && (*bench > 0 || *importFile != "" || *best > 0 || *distribution > 0)) {
+		fmt.Fprintln(os.Stderr, "无效的模式:", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *importFile != "" {
+		os.Exit(runImport(*importFile, pattern))
+	}
+
+	if *seed != "" {
+		if *legacyKeygen {
+			fmt.Fprintln(os.Stderr, "-seed 不能与 -legacy-keygen 同时使用")
+			os.Exit(2)
+		}
+		slog.Warn("使用了 -seed，生成的私钥可被预测，仅用于测试")
+	}
+
+	var ks *keystoreExporter
+	if *keystoreDir != "" {
+		if *password == "" {
+			*password = os.Getenv(passwordEnv)
+		}
+		if *password == "" {
+			fmt.Fprintln(os.Stderr, "-keystore 需要通过 -password 或环境变量"+passwordEnv+"指定密码")
+			os.Exit(2)
+		}
+		ks = newKeystoreExporter(*keystoreDir, *password)
+	}
+
+	var qr *qrWriter
+	if *qrDir != "" {
+		if *qrDir == qrTerminal && *quiet {
+			fmt.Fprintln(os.Stderr, "-qr - 会向stdout打印二维码，不能与 -quiet 同时使用")
+			os.Exit(2)
+		}
+		qr = &qrWriter{dir: *qrDir, keystore: *qrKeystore}
+	}
+	if *qrKeystore && (qr == nil || ks == nil) {
+		fmt.Fprintln(os.Stderr, "-qr-keystore 需要同时指定 -qr 和 -keystore")
+		os.Exit(2)
+	}
+
+	var hook *webhookSender
+	if *webhookURL != "" {
+		h, insecure, err := newWebhookSender(*webhookURL)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -webhook:", err)
+			os.Exit(2)
+		}
+		if insecure {
+			slog.Warn("-webhook 不是HTTPS，私钥将以明文传输", "url", *webhookURL)
+		}
+		hook = h
+	}
+
+	opts := vanity.Options{Workers: *workers, Count: *target, Forever: *forever, LegacyKeygen: *legacyKeygen, WithPubkey: *withPubkey,
+		LockOSThread: *lockThreads}
+	if *limitProcs {
+		// 只调低，不超过运行时按CPU核心数和cgroup限制确定的值
+		procs := min(*workers, runtime.GOMAXPROCS(0))
+		prev := runtime.GOMAXPROCS(procs)
+		slog.Debug("已设置GOMAXPROCS", "gomaxprocs", procs, "previous", prev)
+	}
+	opts.OnSourceError = func(err error) {
+		slog.Warn("生成候选失败，已丢弃并重试；若反复出现请检查系统随机源", "err", err)
+	}
+	if *seed != "" {
+		seedBytes := []byte(*seed)
+		opts.RandSource = func(worker int) io.Reader {
+			return vanity.NewSeededReader(seedBytes, uint64(worker))
+		}
+	}
+	if *deployer != "" || *initCodeHash != "" {
+		c, err := parseCreate2(*deployer, *initCodeHash)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的CREATE2参数:", err)
+			os.Exit(2)
+		}
+		opts.Create2 = c
+	}
+	if *shard != "" {
+		if opts.Create2 == nil {
+			fmt.Fprintln(os.Stderr, "-shard 只适用于CREATE2模式，随机私钥搜索无需分片")
+			os.Exit(2)
+		}
+		i, n, err := parseShard(*shard)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -shard:", err)
+			os.Exit(2)
+		}
+		opts.Create2.Shard, opts.Create2.Shards = i, n
+	}
+	if pattern.Tron && (opts.Create2 != nil || *create) {
+		fmt.Fprintln(os.Stderr, "-chain tron 不能与 CREATE/CREATE2 模式同时使用")
+		os.Exit(2)
+	}
+	if *create {
+		if opts.Create2 != nil {
+			fmt.Fprintln(os.Stderr, "-create 与 CREATE2 参数不能同时指定")
+			os.Exit(2)
+		}
+		opts.Create = &vanity.Create{Nonce: *createNonce}
+	}
+	if *mnemonic {
+		if opts.Create2 != nil || opts.Create != nil {
+			fmt.Fprintln(os.Stderr, "-mnemonic 不能与 CREATE/CREATE2 模式同时指定")
+			os.Exit(2)
+		}
+		if *mnemonicWords != 12 && *mnemonicWords != 24 {
+			fmt.Fprintln(os.Stderr, "-mnemonic-words 必须为12或24")
+			os.Exit(2)
+		}
+		path, err := vanity.ParsePath(*hdPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -hd-path:", err)
+			os.Exit(2)
+		}
+		opts.Mnemonic = &vanity.Mnemonic{Words: *mnemonicWords, Path: path}
+	}
+	if *hdMnemonicFile != "" {
+		if opts.Create2 != nil || opts.Create != nil || opts.Mnemonic != nil {
+			fmt.Fprintln(os.Stderr, "-hd-mnemonic-file 不能与 CREATE/CREATE2 或 -mnemonic 同时指定")
+			os.Exit(2)
+		}
+		h, err := loadHDIndex(*hdMnemonicFile, *hdPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "无效的 -hd-mnemonic-file:", err)
+			os.Exit(2)
+		}
+		opts.HDIndex = h
+	}
+
+	if *pprofAddr != "" {
+		if err := startPprof(*pprofAddr); err != nil {
+			slog.Error("无法启动pprof", "err", err)
+			os.Exit(2)
+		}
+	}
+
+	if *estimate {
+		printEstimate(pattern, opts)
+		return
+	}
+	if *bench > 0 {
+		runBench(opts, *bench)
+		return
+	}
+	if *distribution > 0 {
+		os.Exit(runDistribution(opts, *distribution))
+	}
+	if *countOnly > 0 {
+		os.Exit(runCountOnly(opts, pattern, *countOnly))
+	}
+
+	var stream *streamWriter
+	if *streamJSON {
+		if *output == stdoutPath || *qrDir == qrTerminal {
+			fmt.Fprintln(os.Stderr, "-stream-json 独占标准输出，不能与 -output - 或 -qr - 同时使用")
+			os.Exit(2)
+		}
+		stream = &streamWriter{w: os.Stdout, pattern: pattern.String()}
+	}
+
+	var g *vanity.Generator
+	// -stream-json 时即使 -quiet 也输出进度事件，只是不写日志
+	if *progressInterval > 0 && (!*quiet || stream != nil) {
+		difficulty := pattern.Difficulty()
+		opts.ProgressInterval = *progressInterval
+		opts.OnProgress = func(count int64, elapsed time.Duration) {
+			if stream != nil {
+				stream.progress(count, g.Matches(), elapsed)
+			}
+			if *quiet {
+				return
+			}
+			rate := float64(count) / elapsed.Seconds()
+			attrs := []any{"attempts", count, "elapsed", elapsed.Round(time.Second).String(),
+				"rate", fmt.Sprintf("%.2f", rate), "matches", g.Matches()}
+			if *best == 0 {
+				attrs = append(attrs, "eta", progressETA(difficulty, rate, *target-g.Matches(), *forever))
+			}
+			slog.Info("进度", attrs...)
+		}
+	}
+
+	// SIGINT/SIGTERM取消ctx，worker退出后打印最终统计
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var perMatch *perMatchWriter
+	if *outputPerMatch != "" {
+		if *noFile || *gzipOutput || *truncate || *output != defaultOutput {
+			fmt.Fprintln(os.Stderr, "-output-per-match 不能与 -output、-no-file、-gzip、-truncate 同时使用")
+			os.Exit(2)
+		}
+		perMatch = &perMatchWriter{dir: *outputPerMatch, format: *format, pattern: pattern.String()}
+		if err := perMatch.open(); err != nil {
+			slog.Error("结果目录不可写", "dir", *outputPerMatch, "err", err)
+			os.Exit(1)
+		}
+	}
+	var out *resultLog
+	if !*noFile && perMatch == nil {
+		path := *output
+		if *gzipOutput {
+			if path == stdoutPath {
+				fmt.Fprintln(os.Stderr, "-gzip 不能与 -output - 同时使用")
+				os.Exit(2)
+			}
+			if !strings.HasSuffix(path, ".gz") {
+				path += ".gz"
+			}
+		}
+		out = newResultLog(path, *format, pattern.String(), *gzipOutput, *truncate)
+		if err := out.open(); err != nil {
+			slog.Error("结果文件不可写", "path", path, "err", err)
+			os.Exit(1)
+		}
+	}
+	var seen dedup
+	if *unique {
+		seen = exactSet{}
+		if *uniqueBloom > 0 {
+			seen = newBloomFilter(*uniqueBloom)
+		}
+		// 在生成器中去重，重复的匹配不计入 -count
+		opts.Seen = func(address string) bool {
+			if seen.seen(address) {
+				slog.Debug("跳过重复的匹配", "address", address)
+				return true
+			}
+			return false
+		}
+	}
+	g = vanity.NewGenerator(opts)
+	if !*quiet && *best == 0 {
+		logStartupEstimate(pattern, opts)
+	}
+	startTime := time.Now()
+	// 超时从搜索开始计时，不含预热
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	if *metricsAddr != "" {
+		err := startMetrics(*metricsAddr, func() (int64, int64, time.Duration) {
+			return g.Count(), g.Matches(), time.Since(startTime)
+		})
+		if err != nil {
+			slog.Error("无法启动metrics", "err", err)
+			os.Exit(2)
+		}
+	}
+	var results []vanity.Match // -best 模式的结果
+	onMatch := func(m vanity.Match) {
+		if *no0x {
+			m = trimHexPrefix(m)
+		}
+		if stream != nil {
+			// 标准输出只留给事件流，结果文件照常记录
+			elapsed := time.Since(startTime).Seconds()
+			stream.match(m, elapsed)
+			if out != nil {
+				out.logResult(m, elapsed)
+			}
+		} else {
+			printStats(startTime, m, out, *quiet, *abi)
+		}
+		if perMatch != nil {
+			if path := perMatch.write(m, time.Since(startTime).Seconds()); path != "" {
+				slog.Info("已写入结果文件", "address", m.ChecksumAddress, "path", path)
+			}
+		}
+		logMatch(m)
+		var keystorePath string
+		if ks != nil {
+			keystorePath = ks.export(m)
+		}
+		if qr != nil {
+			qr.write(m, keystorePath)
+		}
+		if hook != nil {
+			hook.send(m)
+		}
+	}
+	if *best > 0 {
+		slog.Info("启动", "workers", g.Workers(), "best", *best, "duration", duration.String())
+		if *duration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *duration)
+			defer cancel()
+		}
+		results, err = g.Best(ctx, *best, vanity.ScoreLeadingZeros)
+		for _, m := range results {
+			// Best不经过Options.Seen
+			if seen != nil && seen.seen(m.Address) {
+				continue
+			}
+			onMatch(m)
+		}
+	} else {
+		slog.Info("启动", "workers", g.Workers(), "pattern", pattern.String())
+		err = g.Run(ctx, pattern, onMatch)
+	}
+	if out != nil {
+		out.close()
+	}
+	if hook != nil {
+		hook.close()
+	}
+	// -forever 时超时是预期的结束方式，不算失败
+	timedOut := errors.Is(err, context.DeadlineExceeded) && !*forever
+	failed := err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	if failed {
+		slog.Error("搜索失败", "err", err)
+	} else if timedOut {
+		slog.Warn("超时，未找到足够的匹配", "timeout", timeout.String())
+	} else if err != nil && !*forever {
+		slog.Info("搜索已中断", "err", err)
+	}
+
+	elapsed := time.Since(startTime)
+	total := g.Count()
+	matched := fmt.Sprintf("%d/%d", g.Matches(), *target)
+	if *forever {
+		matched = strconv.FormatInt(g.Matches(), 10)
+	}
+	if *best > 0 {
+		matched = strconv.Itoa(len(results))
+	}
+	slog.Info("结束", "elapsed", elapsed.Round(time.Millisecond).String(), "attempts", total,
+		"rate", fmt.Sprintf("%.2f", float64(total)/elapsed.Seconds()), "matches", matched)
+	logWorkerStats(g.WorkerCounts(), total)
+	if failed {
+		os.Exit(1)
+	}
+	if timedOut {
+		os.Exit(exitTimeout)
+	}
+}
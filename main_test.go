@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatETA(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "0s"},
+		{90.4, "1m30s"},
+		{3600 * 24, "24h0m0s"},
+		{maxETASeconds * 2, "约585年"},
+		{1e20, "约3.17e+12年"},
+	}
+	for _, tt := range tests {
+		if got := formatETA(tt.seconds); got != tt.want {
+			t.Errorf("formatETA(%v) = %q，期望 %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestPrintETANotNegative(t *testing.T) {
+	// -prefix deadbeefdeadbeef 按每秒十万个地址
+	mean, p50, p90 := etaQuantiles(1<<64, 1e5)
+	for _, eta := range []string{mean, p50, p90} {
+		if strings.HasPrefix(eta, "-") || !strings.HasSuffix(eta, "年") {
+			t.Errorf("用时 %q 应以年表示", eta)
+		}
+	}
+}
+
+func TestStartupQuantilesNotNegative(t *testing.T) {
+	// 12位前缀在慢速机器上，90%分位的尝试次数先于平均值超出time.Duration范围
+	d := float64(uint64(1) << 48)
+	for _, rate := range []float64{1e4, 3e4, 1e5} {
+		mean, p50, p90 := etaQuantiles(d, rate)
+		for _, eta := range []string{mean, p50, p90} {
+			if strings.HasPrefix(eta, "-") {
+				t.Errorf("速度 %v 时用时为负: %q", rate, eta)
+			}
+		}
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	tests := []struct {
+		d, rate   float64
+		remaining int64
+		forever   bool
+		want      string
+	}{
+		{0, 1e5, 1, false, "未知"},
+		{16, 0, 1, false, "未知"},
+		{16, 16, 3, false, "3s"},
+		{16, 16, 3, true, "1s"},
+		{1 << 64, 1e5, 1, false, "约5.85e+06年"},
+		{1 << 64, 1e5, 10, false, "约5.85e+07年"},
+	}
+	for _, tt := range tests {
+		if got := progressETA(tt.d, tt.rate, tt.remaining, tt.forever); got != tt.want {
+			t.Errorf("progressETA(%v, %v, %d, %v) = %q，期望 %q", tt.d, tt.rate, tt.remaining, tt.forever, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixBody(t *testing.T) {
+	tests := []struct {
+		prefix, chain, want string
+	}{
+		{"dead", "ethereum", "dead"},
+		{"0xdead", "ethereum", "dead"},
+		{"0Xdead", "ethereum", "dead"},
+		{"0xDeAd", "ethereum", "DeAd"},
+		{"0x", "ethereum", ""},
+		{"00dead", "ethereum", "00dead"},
+		{"0x0xdead", "ethereum", "0xdead"},
+		{"TXyz", "tron", "TXyz"},
+		{"0xdead", "tron", "0xdead"},
+	}
+	for _, tt := range tests {
+		if got := prefixBody(tt.prefix, tt.chain); got != tt.want {
+			t.Errorf("prefixBody(%q, %q) = %q，期望 %q", tt.prefix, tt.chain, got, tt.want)
+		}
+	}
+}
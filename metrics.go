@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// metricsStats 返回抓取时的尝试次数、匹配数和搜索用时
+type metricsStats func() (attempts, matches int64, elapsed time.Duration)
+
+// startMetrics 在addr上以Prometheus文本格式提供/metrics，只在被抓取时读取计数，不影响搜索热循环
+func startMetrics(addr string, stats metricsStats) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		attempts, matches, elapsed := stats()
+		rate := 0.0
+		if elapsed > 0 {
+			rate = float64(attempts) / elapsed.Seconds()
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		fmt.Fprintf(w, "# HELP attempts_total Total candidate addresses generated.\n# TYPE attempts_total counter\nattempts_total %d\n", attempts)
+		fmt.Fprintf(w, "# HELP matches_total Total addresses matching the pattern.\n# TYPE matches_total counter\nmatches_total %d\n", matches)
+		fmt.Fprintf(w, "# HELP addresses_per_second Average generation rate since the search started.\n# TYPE addresses_per_second gauge\naddresses_per_second %g\n", rate)
+	})
+	bound, err := serveHTTP(addr, "metrics", mux)
+	if err != nil {
+		return err
+	}
+	slog.Info("metrics已启动", "url", fmt.Sprintf("http://%s/metrics", bound))
+	return nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// jsonRecord JSON格式的一条结果，每行一个对象
+type jsonRecord struct {
+	Address         string  `json:"address"`
+	ChecksumAddress string  `json:"checksumAddress"`
+	PrivateKey      string  `json:"privateKey,omitempty"`
+	Salt            string  `json:"salt,omitempty"`
+	Sender          string  `json:"sender,omitempty"`
+	Mnemonic        string  `json:"mnemonic,omitempty"`
+	Path            string  `json:"path,omitempty"`
+	PublicKey       string  `json:"publicKey,omitempty"`
+	CompressedKey   string  `json:"compressedPublicKey,omitempty"`
+	RepeatRun       int     `json:"repeatRun,omitempty"`
+	MatchedPattern  string  `json:"matchedPattern,omitempty"`
+	Tron            string  `json:"tron,omitempty"`
+	ICAP            string  `json:"icap,omitempty"`
+	Attempts        int64   `json:"attempts"`
+	ElapsedSeconds  float64 `json:"elapsedSeconds"`
+	Timestamp       string  `json:"timestamp"`
+	Pattern         string  `json:"pattern,omitempty"`
+}
+
+// newJSONRecord 由匹配生成JSON结果记录
+func newJSONRecord(m vanity.Match, duration float64, timestamp, pattern string) jsonRecord {
+	return jsonRecord{
+		Address:         m.Address,
+		ChecksumAddress: m.ChecksumAddress,
+		PrivateKey:      m.PrivateKey,
+		Salt:            m.Salt,
+		Sender:          m.Sender,
+		Mnemonic:        m.Mnemonic,
+		Path:            m.Path,
+		PublicKey:       m.PublicKey,
+		CompressedKey:   m.CompressedKey,
+		RepeatRun:       m.RepeatRun,
+		MatchedPattern:  m.MatchedPattern,
+		Tron:            m.Tron,
+		ICAP:            m.ICAP,
+		Attempts:        m.Attempts,
+		ElapsedSeconds:  duration,
+		Timestamp:       timestamp,
+		Pattern:         pattern,
+	}
+}
+
+// csvHeader CSV格式的表头，仅在文件为空时写入一次
+var csvHeader = []string{"address", "checksum", "private_key", "attempts", "elapsed_seconds", "timestamp", "salt", "sender", "mnemonic", "public_key", "compressed_public_key"}
+
+// formatCSV 生成一行CSV，header为true时先写表头
+func formatCSV(m vanity.Match, duration float64, timestamp string, header bool) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if header {
+		w.Write(csvHeader)
+	}
+	w.Write([]string{
+		m.Address,
+		m.ChecksumAddress,
+		m.PrivateKey,
+		strconv.FormatInt(m.Attempts, 10),
+		strconv.FormatFloat(duration, 'f', 2, 64),
+		timestamp,
+		m.Salt,
+		m.Sender,
+		m.Mnemonic,
+		m.PublicKey,
+		m.CompressedKey,
+	})
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// formatRecord 按格式生成一条结果记录，pattern为产生该结果的模式描述，newFile表示目标文件为空
+func formatRecord(m vanity.Match, duration float64, format, pattern string, newFile bool) (string, error) {
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	switch format {
+	case "csv":
+		return formatCSV(m, duration, timestamp, newFile)
+	case "json":
+		b, err := json.Marshal(newJSONRecord(m, duration, timestamp, pattern))
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	}
+
+	content := fmt.Sprintf("%s\n%s\n", m.Address, m.ChecksumAddress)
+	if m.PrivateKey != "" {
+		content += m.PrivateKey + "\n"
+	}
+	content += fmt.Sprintf("%d\n%.2f\n%d\n", m.Attempts, duration, m.LeadingZeros)
+	if m.Salt != "" {
+		content += m.Salt + "\n"
+	}
+	if m.Sender != "" {
+		content += m.Sender + "\n"
+	}
+	if m.Mnemonic != "" {
+		content += m.Mnemonic + "\n"
+	}
+	if m.Path != "" {
+		content += m.Path + "\n"
+	}
+	if m.PublicKey != "" {
+		content += m.PublicKey + "\n" + m.CompressedKey + "\n"
+	}
+	if m.RepeatRun > 0 {
+		content += fmt.Sprintf("%d\n", m.RepeatRun)
+	}
+	if m.MatchedPattern != "" {
+		content += m.MatchedPattern + "\n"
+	}
+	if m.Tron != "" {
+		content += m.Tron + "\n"
+	}
+	if m.ICAP != "" {
+		content += m.ICAP + "\n"
+	}
+	content += timestamp + "\n"
+	if pattern != "" {
+		content += pattern + "\n"
+	}
+	return content + "\n", nil
+}
+
+// defaultOutput 默认结果文件
+const defaultOutput = "add.txt"
+
+// stdoutPath 表示结果写到标准输出的 -output 值
+const stdoutPath = "-"
+
+// flushInterval 结果文件缓冲的定时刷新间隔，异常退出时最多丢失这段时间内的结果
+const flushInterval = time.Second
+
+// maxPendingRecords 缓冲中最多积压的记录数，达到时立即同步刷新
+// 模式很宽松时匹配可能比磁盘写得快，同步刷新使调用方（进而整个搜索）放慢，内存占用保持有界
+const maxPendingRecords = 1024
+
+// writeRetries、writeBackoff 写结果文件失败时的重试次数和首次重试前的等待，之后每次加倍
+const (
+	writeRetries = 4
+	writeBackoff = 50 * time.Millisecond
+)
+
+// retryWriter 写入失败时按指数退避重试，应对磁盘暂时写满、文件被锁等短暂错误，重试用尽后返回最后的错误
+type retryWriter struct {
+	w       io.Writer
+	path    string
+	retries int
+	backoff time.Duration
+}
+
+// Write 写入p，部分写入后从剩余部分继续重试
+func (r retryWriter) Write(p []byte) (int, error) {
+	written := 0
+	delay := r.backoff
+	for attempt := 0; ; attempt++ {
+		n, err := r.w.Write(p[written:])
+		written += n
+		if err == nil {
+			return written, nil
+		}
+		if attempt == r.retries {
+			return written, err
+		}
+		slog.Warn("写入结果文件失败，稍后重试", "path", r.path, "retry", attempt+1, "err", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// resultLog 结果文件，运行期间保持打开并经过缓冲，定时和关闭时刷新
+type resultLog struct {
+	format   string     // text、json 或 csv
+	path     string     // 结果文件路径，为stdoutPath时写到sink
+	pattern  string     // 搜索模式的描述，写入text和json记录
+	gzip     bool       // 经gzip压缩写入，追加到已有文件时新增一个gzip成员
+	truncate bool       // 打开时清空已有内容而不是追加
+	sink     io.Writer  // 非nil时写到该writer而不是path指定的文件，如标准输出
+	live     bool       // 每条记录立即刷新，写到标准输出时管道另一端能及时读到
+	stderr   io.Writer  // 写入失败时改写到的输出，nil时为标准错误
+	mu       sync.Mutex // 串行化写入，避免多个匹配的内容交错
+
+	file    *os.File
+	gz      *gzip.Writer // gzip时位于w与file之间
+	w       *bufio.Writer
+	newFile bool          // 文件为空，用于只写一次CSV表头
+	done    chan struct{} // 关闭时停止定时刷新
+	pending []string      // 上次成功刷新后写入缓冲的记录，重试后仍写入失败时改写到标准错误
+}
+
+// newResultLog 创建写到path的结果文件，path为stdoutPath时写到标准输出，调用open后才能写入
+func newResultLog(path, format, pattern string, gzip, truncate bool) *resultLog {
+	if path == stdoutPath {
+		r := newResultSink(os.Stdout, format, pattern, gzip)
+		r.live = true
+		return r
+	}
+	return &resultLog{format: format, path: path, pattern: pattern, gzip: gzip, truncate: truncate}
+}
+
+// newResultSink 创建写到w的结果输出，格式和压缩与结果文件相同，w由调用方负责关闭
+func newResultSink(w io.Writer, format, pattern string, gzip bool) *resultLog {
+	return &resultLog{format: format, path: stdoutPath, pattern: pattern, gzip: gzip, sink: w}
+}
+
+// open 创建结果文件所在目录并打开文件，便于在开始搜索前报错；指定了sink时直接写到sink
+func (r *resultLog) open() error {
+	dst := r.sink
+	if dst == nil {
+		file, err := r.openFile()
+		if err != nil {
+			return err
+		}
+		dst = file
+	} else {
+		r.newFile = true
+	}
+	dst = retryWriter{w: dst, path: r.path, retries: writeRetries, backoff: writeBackoff}
+	if r.gzip {
+		r.gz = gzip.NewWriter(dst)
+		dst = r.gz
+	}
+	r.w = bufio.NewWriter(dst)
+	r.done = make(chan struct{})
+	go r.flushLoop()
+	return nil
+}
+
+// openFile 创建path所在目录并打开结果文件，记录文件是否为空
+func (r *resultLog) openFile() (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return nil, err
+	}
+	flags := os.O_APPEND | os.O_CREATE | os.O_WRONLY
+	if r.truncate {
+		flags = os.O_TRUNC | os.O_CREATE | os.O_WRONLY
+	}
+	file, err := os.OpenFile(r.path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	// 追加到已有内容的文件时不重复写CSV表头，清空后的文件视为新文件
+	r.file, r.newFile = file, info.Size() == 0
+	return file, nil
+}
+
+// flushLoop 每隔flushInterval刷新一次缓冲
+func (r *resultLog) flushLoop() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			// close可能已在等待锁期间关闭了文件
+			r.mu.Lock()
+			if r.w != nil {
+				r.flush()
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// flush 刷新缓冲，gzip时同时刷新压缩流使已写内容可被解压，调用方须持有mu
+// 重试后仍失败时把尚未确认写入的记录打印到标准错误，找到的私钥不会因磁盘满或文件被删而丢失
+func (r *resultLog) flush() {
+	err := r.w.Flush()
+	if err == nil && r.gz != nil {
+		err = r.gz.Flush()
+	}
+	if err != nil {
+		slog.Error("写入结果文件失败，结果改为输出到标准错误", "path", r.path, "err", err)
+		r.fallback()
+		return
+	}
+	r.pending = r.pending[:0]
+}
+
+// fallback 把pending中的记录写到标准错误并清空，调用方须持有mu
+// 用标准错误而不是标准输出，以免混入 -quiet 时供脚本解析的输出
+func (r *resultLog) fallback() {
+	for _, content := range r.pending {
+		io.WriteString(r.fallbackWriter(), content)
+	}
+	r.pending = r.pending[:0]
+}
+
+// fallbackWriter 返回写入失败时改写到的输出
+func (r *resultLog) fallbackWriter() io.Writer {
+	if r.stderr != nil {
+		return r.stderr
+	}
+	return os.Stderr
+}
+
+// close 刷新剩余结果并关闭文件，sink由调用方负责关闭
+func (r *resultLog) close() {
+	if r.w == nil {
+		return
+	}
+	close(r.done)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flush()
+	// gzip.Writer.Close写入结尾，否则文件会被视为截断
+	if r.gz != nil {
+		if err := r.gz.Close(); err != nil {
+			slog.Error("写入结果文件失败", "path", r.path, "err", err)
+		}
+	}
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			slog.Error("关闭结果文件失败", "path", r.path, "err", err)
+		}
+	}
+	r.file, r.w = nil, nil
+}
+
+// logResult 记录结果到文件
+func (r *resultLog) logResult(m vanity.Match, duration float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.w == nil {
+		// 文件已关闭，仍把结果打到标准错误，不丢弃
+		if content, err := formatRecord(m, duration, r.format, r.pattern, false); err == nil {
+			io.WriteString(r.fallbackWriter(), content)
+		}
+		return
+	}
+
+	content, err := formatRecord(m, duration, r.format, r.pattern, r.newFile)
+	if err != nil {
+		// text格式不会失败，改以text格式输出到标准错误
+		slog.Error("格式化结果失败，结果改为输出到标准错误", "err", err)
+		content, _ = formatRecord(m, duration, "text", r.pattern, false)
+		io.WriteString(r.fallbackWriter(), content)
+		return
+	}
+	r.newFile = false
+	r.pending = append(r.pending, content)
+	// 重试用尽后错误会保留在bufio.Writer中，之后的写入都会失败，改写到标准错误
+	if _, err := r.w.WriteString(content); err != nil {
+		slog.Error("写入结果文件失败，结果改为输出到标准错误", "path", r.path, "err", err)
+		r.fallback()
+		return
+	}
+	if r.live || len(r.pending) >= maxPendingRecords {
+		r.flush()
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// testMatch 固定内容的匹配，用于检查写入结果的格式
+var testMatch = vanity.Match{
+	Address:         "0xa0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9",
+	ChecksumAddress: "0xA0b1C2d3E4f5A6b7C8d9E0f1A2b3C4d5E6f7A8b9",
+	PrivateKey:      strings.Repeat("ab", 32),
+	Attempts:        42,
+}
+
+// writeSink 把matches写到内存中的结果输出，关闭后返回写入的内容
+func writeSink(t *testing.T, format string, gz bool, matches ...vanity.Match) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	out := newResultSink(&buf, format, "prefix=a", gz)
+	if err := out.open(); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range matches {
+		out.logResult(m, 1.5)
+	}
+	out.close()
+	return buf.Bytes()
+}
+
+func TestResultSinkText(t *testing.T) {
+	got := string(writeSink(t, "text", false, testMatch))
+	for _, want := range []string{testMatch.Address, testMatch.ChecksumAddress, testMatch.PrivateKey, "42\n1.50\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("text输出缺少 %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestResultSinkJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(writeSink(t, "json", false, testMatch, testMatch))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("期望2行JSON，实际%d行", len(lines))
+	}
+	var rec jsonRecord
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Address != testMatch.Address || rec.PrivateKey != testMatch.PrivateKey || rec.Attempts != 42 || rec.Pattern != "prefix=a" {
+		t.Errorf("JSON记录不符: %+v", rec)
+	}
+}
+
+func TestResultSinkCSVHeaderOnce(t *testing.T) {
+	got := string(writeSink(t, "csv", false, testMatch, testMatch))
+	if n := strings.Count(got, strings.Join(csvHeader, ",")); n != 1 {
+		t.Errorf("CSV表头出现%d次:\n%s", n, got)
+	}
+}
+
+func TestResultSinkGzip(t *testing.T) {
+	zr, err := gzip.NewReader(bytes.NewReader(writeSink(t, "json", true, testMatch)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec jsonRecord
+	if err := json.Unmarshal(plain, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.ChecksumAddress != testMatch.ChecksumAddress {
+		t.Errorf("解压后的记录不符: %+v", rec)
+	}
+}
+
+// gateWriter 在gate关闭前阻塞每次写入，模拟卡住的磁盘或管道
+type gateWriter struct {
+	gate chan struct{}
+	bytes.Buffer
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	return w.Buffer.Write(p)
+}
+
+// slowWriter 每次写入前等待一小段时间，模拟慢速磁盘
+type slowWriter struct{ bytes.Buffer }
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	return w.Buffer.Write(p)
+}
+
+func TestResultLogPendingBounded(t *testing.T) {
+	w := &slowWriter{}
+	out := newResultSink(w, "json", "", false)
+	if err := out.open(); err != nil {
+		t.Fatal(err)
+	}
+	records := 3 * maxPendingRecords
+	for i := 0; i < records; i++ {
+		out.logResult(testMatch, 0)
+		out.mu.Lock()
+		pending := len(out.pending)
+		out.mu.Unlock()
+		if pending > maxPendingRecords {
+			t.Fatalf("第%d条后积压 %d 条，超过 %d", i+1, pending, maxPendingRecords)
+		}
+	}
+	out.close()
+	if n := strings.Count(w.String(), "\n"); n != records {
+		t.Fatalf("写入 %d 条，期望 %d", n, records)
+	}
+}
+
+func TestResultLogBackpressure(t *testing.T) {
+	w := &gateWriter{gate: make(chan struct{})}
+	out := newResultSink(w, "json", "", false)
+	if err := out.open(); err != nil {
+		t.Fatal(err)
+	}
+	records := 2 * maxPendingRecords
+	var logged atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < records; i++ {
+			out.logResult(testMatch, 0)
+			logged.Add(1)
+		}
+	}()
+	// 写入阻塞时logResult随之阻塞，不会把全部记录积压在内存中
+	time.Sleep(200 * time.Millisecond)
+	if n := logged.Load(); n >= maxPendingRecords {
+		t.Fatalf("写入阻塞时仍记录了 %d 条", n)
+	}
+	close(w.gate)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("写入恢复后logResult仍未返回")
+	}
+	out.close()
+	if n := strings.Count(w.String(), "\n"); n != records {
+		t.Fatalf("写入 %d 条，期望 %d", n, records)
+	}
+}
+
+// flakyWriter 前fails次写入只写一半并失败，之后正常写入，记录写入次数
+type flakyWriter struct {
+	fails  int
+	writes int
+	bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.fails < 0 || w.writes <= w.fails {
+		n, _ := w.Buffer.Write(p[:len(p)/2])
+		return n, errors.New("磁盘已满")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestRetryWriter(t *testing.T) {
+	w := &flakyWriter{fails: 3}
+	rw := retryWriter{w: w, retries: 4, backoff: time.Millisecond}
+	n, err := rw.Write([]byte("0123456789abcdef"))
+	if err != nil || n != 16 || w.String() != "0123456789abcdef" {
+		t.Fatalf("Write() = %d, %v，写入 %q", n, err, w.String())
+	}
+	if w.writes != 4 {
+		t.Errorf("写入 %d 次，期望失败3次后成功共4次", w.writes)
+	}
+
+	w = &flakyWriter{fails: -1}
+	rw.w = w
+	if _, err := rw.Write([]byte("0123456789abcdef")); err == nil {
+		t.Fatal("一直失败时Write应返回错误")
+	}
+	if w.writes != rw.retries+1 {
+		t.Errorf("写入 %d 次，期望 %d", w.writes, rw.retries+1)
+	}
+}
+
+func TestResultLogFallback(t *testing.T) {
+	w := &flakyWriter{fails: -1}
+	var stderr bytes.Buffer
+	out := newResultSink(w, "json", "", false)
+	out.stderr = &stderr
+	if err := out.open(); err != nil {
+		t.Fatal(err)
+	}
+	out.logResult(testMatch, 0)
+	out.close()
+	if w.writes != writeRetries+1 {
+		t.Errorf("写入 %d 次，期望 %d", w.writes, writeRetries+1)
+	}
+	var rec jsonRecord
+	if err := json.Unmarshal(stderr.Bytes(), &rec); err != nil || rec.PrivateKey != testMatch.PrivateKey {
+		t.Fatalf("重试用尽后记录未改写到后备输出: %q", stderr.String())
+	}
+}
+
+// BenchmarkResultSink 经bufio缓冲写入结果文件，定时和积压到上限时才刷新
+func BenchmarkResultSink(b *testing.B) {
+	out := newResultLog(filepath.Join(b.TempDir(), "results.json"), "json", "prefix=a", false, false)
+	if err := out.open(); err != nil {
+		b.Fatal(err)
+	}
+	defer out.close()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		out.logResult(testMatch, 1.5)
+	}
+}
+
+// BenchmarkResultSinkUnbuffered 每条记录直接写入文件，即改为缓冲之前的做法
+func BenchmarkResultSinkUnbuffered(b *testing.B) {
+	file, err := os.Create(filepath.Join(b.TempDir(), "results.json"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		content, err := formatRecord(testMatch, 1.5, "json", "prefix=a", false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := file.WriteString(content); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// perMatchWriter 把每个匹配写到目录下各自的文件，文件名取自地址，便于逐个导入
+type perMatchWriter struct {
+	dir     string // 输出目录，不存在时创建
+	format  string // text、json 或 csv，决定文件内容和扩展名
+	pattern string // 搜索模式的描述，写入text和json记录
+}
+
+// perMatchExt 各结果格式对应的文件扩展名
+var perMatchExt = map[string]string{"text": ".txt", "json": ".json", "csv": ".csv"}
+
+// open 创建输出目录，便于在开始搜索前报错；目录只允许当前用户访问
+func (p *perMatchWriter) open() error {
+	return os.MkdirAll(p.dir, 0o700)
+}
+
+// write 把m写到新文件并返回其路径；同名文件已存在时依次加-1、-2等后缀，不覆盖
+// 写入失败时把记录打印到标准错误，不丢失私钥
+func (p *perMatchWriter) write(m vanity.Match, duration float64) string {
+	content, err := formatRecord(m, duration, p.format, p.pattern, true)
+	if err != nil {
+		slog.Error("格式化结果失败，结果改为输出到标准错误", "err", err)
+		content, _ = formatRecord(m, duration, "text", p.pattern, false)
+		os.Stderr.WriteString(content)
+		return ""
+	}
+	name := sanitizeFilename(matchName(m))
+	for i := 0; ; i++ {
+		path := filepath.Join(p.dir, name+perMatchExt[p.format])
+		if i > 0 {
+			path = filepath.Join(p.dir, fmt.Sprintf("%s-%d%s", name, i, perMatchExt[p.format]))
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err == nil {
+			_, err = f.WriteString(content)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			slog.Error("写入结果文件失败，结果改为输出到标准错误", "path", path, "err", err)
+			os.Stderr.WriteString(content)
+			return ""
+		}
+		return path
+	}
+}
+
+// matchName 返回用作文件名的地址：Tron、ICAP模式为对应编码，否则为校验和地址
+func matchName(m vanity.Match) string {
+	switch {
+	case m.Tron != "":
+		return m.Tron
+	case m.ICAP != "":
+		return m.ICAP
+	}
+	return m.ChecksumAddress
+}
+
+// sanitizeFilename 只保留字母、数字、点、横线和下划线，其余字符换成下划线，并去掉开头的点
+func sanitizeFilename(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+	s = strings.TrimLeft(s, ".")
+	if s == "" {
+		return "match"
+	}
+	return s
+}
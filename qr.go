@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/dmqf12/ethaddress/vanity"
+	"github.com/skip2/go-qrcode"
+)
+
+// qrTerminal -qr 的取值，表示在终端打印而不是写PNG文件
+const qrTerminal = "-"
+
+// qrPNGSize PNG二维码的边长（像素）
+const qrPNGSize = 512
+
+// qrWriter 为每个匹配生成地址的二维码，便于手机扫码导入
+type qrWriter struct {
+	dir      string // PNG输出目录，为qrTerminal时打印到终端
+	keystore bool   // 同时为keystore JSON生成二维码
+}
+
+// write 生成m的地址二维码；keystorePath非空且启用了keystore时再生成keystore JSON的二维码
+func (q *qrWriter) write(m vanity.Match, keystorePath string) {
+	q.encode(m.ChecksumAddress, m.ChecksumAddress)
+	if !q.keystore || keystorePath == "" {
+		return
+	}
+	data, err := os.ReadFile(keystorePath)
+	if err != nil {
+		slog.Error("读取keystore失败", "path", keystorePath, "err", err)
+		return
+	}
+	q.encode(m.ChecksumAddress+"-keystore", string(data))
+}
+
+// encode 将content编码为二维码，写入dir下的name.png或打印到终端
+func (q *qrWriter) encode(name, content string) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		slog.Error("生成二维码失败", "name", name, "err", err)
+		return
+	}
+	if q.dir == qrTerminal {
+		fmt.Print(code.ToSmallString(false))
+		return
+	}
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		slog.Error("创建二维码目录失败", "dir", q.dir, "err", err)
+		return
+	}
+	png, err := code.PNG(qrPNGSize)
+	if err != nil {
+		slog.Error("生成二维码失败", "name", name, "err", err)
+		return
+	}
+	path := filepath.Join(q.dir, name+".png")
+	if err := os.WriteFile(path, png, 0o600); err != nil {
+		slog.Error("写入二维码失败", "path", path, "err", err)
+		return
+	}
+	slog.Info("已写入二维码", "path", path)
+}
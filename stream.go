@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// streamProgress -stream-json 的进度事件
+type streamProgress struct {
+	Type           string  `json:"type"` // 固定为"progress"
+	Attempts       int64   `json:"attempts"`
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+	Rate           float64 `json:"rate"`
+	Matches        int64   `json:"matches"`
+	Timestamp      string  `json:"timestamp"`
+}
+
+// streamMatch -stream-json 的匹配事件，字段与 -format json 的结果记录相同
+type streamMatch struct {
+	Type string `json:"type"` // 固定为"match"
+	jsonRecord
+}
+
+// streamWriter 把匹配和进度以每行一个JSON对象的形式写到w，供前端实时读取
+type streamWriter struct {
+	w       io.Writer
+	pattern string     // 搜索模式的描述，写入匹配事件
+	mu      sync.Mutex // 进度和匹配来自不同的goroutine，避免两行交错
+}
+
+// progress 写一条进度事件
+func (s *streamWriter) progress(count, matches int64, elapsed time.Duration) {
+	s.emit(streamProgress{
+		Type:           "progress",
+		Attempts:       count,
+		ElapsedSeconds: elapsed.Seconds(),
+		Rate:           float64(count) / elapsed.Seconds(),
+		Matches:        matches,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// match 写一条匹配事件
+func (s *streamWriter) match(m vanity.Match, duration float64) {
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	s.emit(streamMatch{Type: "match", jsonRecord: newJSONRecord(m, duration, timestamp, s.pattern)})
+}
+
+// emit 编码v并写成一行，立即写出不经缓冲
+func (s *streamWriter) emit(v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("格式化事件失败", "err", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(b, '\n')); err != nil {
+		slog.Error("写入事件流失败", "err", err)
+	}
+}
@@ -0,0 +1,280 @@
+// Package vanity 提供以太坊靓号地址的生成与匹配
+//
+// 从Go代码中搜索前缀：
+//
+//	g := vanity.NewGenerator(vanity.Options{Workers: 4, Count: 1})
+//	err := g.Run(ctx, vanity.Pattern{Prefix: "dead"}, func(m vanity.Match) {
+//		fmt.Println(m.Address, m.PrivateKey)
+//	})
+//
+// 也可以用配置项构造，未指定的项取默认值（worker数为CPU核心数，Count为1，Output为nil即不写）：
+//
+//	g := vanity.NewGenerator(vanity.WithWorkers(4), vanity.WithPattern(vanity.Pattern{Prefix: "dead"}),
+//		vanity.WithOutput(os.Stdout), vanity.WithChecksumMatch(true))
+//	err := g.Search(ctx, func(vanity.Match) {})
+package vanity
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak256 计算Keccak-256哈希（以太坊标准），多个参数依次写入，相当于对其拼接后的内容求哈希
+func Keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		hash.Write(b)
+	}
+	return hash.Sum(nil)
+}
+
+// keccakPool 复用Keccak256Hash的哈希器，避免每次调用分配哈希状态
+var keccakPool = sync.Pool{New: func() any { return newKeccakHasher() }}
+
+// Keccak256Hash 与Keccak256相同，但返回定长数组且复用哈希器，不为结果和哈希状态分配内存
+// 多段输入依次写入，如CREATE2的 0xff ++ deployer ++ salt ++ initCodeHash 不必先拼接到临时缓冲
+func Keccak256Hash(data ...[]byte) [32]byte {
+	k := keccakPool.Get().(*keccakHasher)
+	var out [32]byte
+	copy(out[:], k.sum(data...))
+	keccakPool.Put(k)
+	return out
+}
+
+// keccakHasher 可复用的Keccak-256哈希器，worker各持一个以免热循环中每次分配，不是并发安全的
+type keccakHasher struct {
+	h   hash.Hash
+	out [32]byte
+}
+
+// newKeccakHasher 创建可复用的哈希器
+func newKeccakHasher() *keccakHasher {
+	return &keccakHasher{h: sha3.NewLegacyKeccak256()}
+}
+
+// sum 计算data依次拼接后的Keccak-256，返回的切片在下次调用前有效
+func (k *keccakHasher) sum(data ...[]byte) []byte {
+	k.h.Reset()
+	for _, b := range data {
+		k.h.Write(b)
+	}
+	return k.h.Sum(k.out[:0])
+}
+
+// curveOrder secp256k1的曲线阶N，包内只读
+var curveOrder = secp256k1.S256().Params().N
+
+// validScalar 判断d是否为有效的私钥标量，即在[1, N)内；各处的私钥范围检查都用它
+func validScalar(d *big.Int) bool {
+	return d.Sign() > 0 && d.Cmp(curveOrder) < 0
+}
+
+// GenerateKey 生成随机私钥
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(secp256k1.S256(), rand.Reader)
+}
+
+// ErrWeakKey 随机源产生了0、1或不小于曲线阶的私钥，正常随机源出现的概率约为2^-127，出现即说明随机数生成器可能有问题
+var ErrWeakKey = errors.New("随机源产生了无效的私钥（0、1或不小于曲线阶），随机数生成器可能有问题")
+
+// NewFastKeygen 返回直接从r读取32字节生成私钥的函数，跳过ecdsa.GenerateKey的额外开销
+// r经过缓冲，返回的函数不是并发安全的；值为0、1或不小于N时丢弃并返回ErrWeakKey，公钥不在曲线上时也返回错误，
+// 调用方重试即相当于重读，分布仍然均匀
+// 缓冲中每32字节取出后立即清零，不在内存中留下用过的私钥
+func NewFastKeygen(r io.Reader) func() (*ecdsa.PrivateKey, error) {
+	curve := secp256k1.S256()
+	buf := make([]byte, 32*128)
+	off := len(buf)
+	return func() (*ecdsa.PrivateKey, error) {
+		if off == len(buf) {
+			if _, err := io.ReadFull(r, buf); err != nil {
+				clear(buf)
+				return nil, err
+			}
+			off = 0
+		}
+		chunk := buf[off : off+32]
+		off += 32
+		d := new(big.Int).SetBytes(chunk)
+		clear(chunk)
+		if !validScalar(d) || d.Cmp(big.NewInt(1)) == 0 {
+			wipeScalar(d)
+			return nil, ErrWeakKey
+		}
+		priv := privateKeyFromScalar(d)
+		if !curve.IsOnCurve(priv.X, priv.Y) {
+			wipeScalar(d)
+			return nil, errors.New("私钥对应的公钥不在曲线上")
+		}
+		return priv, nil
+	}
+}
+
+// privateKeyFromScalar 由标量d构造secp256k1私钥并计算公钥
+func privateKeyFromScalar(d *big.Int) *ecdsa.PrivateKey {
+	priv := &ecdsa.PrivateKey{D: d}
+	priv.Curve = secp256k1.S256()
+	scalar := padScalar(d)
+	priv.X, priv.Y = priv.Curve.ScalarBaseMult(scalar)
+	clear(scalar)
+	return priv
+}
+
+// wipeScalar 清零big.Int底层的字，再将其置为0
+func wipeScalar(d *big.Int) {
+	clear(d.Bits())
+	d.SetInt64(0)
+}
+
+// ParsePrivateKey 解析64位十六进制私钥（可带0x前缀），要求其值在[1, N)内
+func ParsePrivateKey(s string) (*ecdsa.PrivateKey, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s) != 64 {
+		return nil, fmt.Errorf("私钥长度应为64位十六进制，实际%d位", len(s))
+	}
+	var b [32]byte
+	if _, err := hex.Decode(b[:], []byte(s)); err != nil {
+		return nil, errors.New("私钥包含非十六进制字符")
+	}
+	d := new(big.Int).SetBytes(b[:])
+	clear(b[:])
+	if !validScalar(d) {
+		return nil, errors.New("私钥不在曲线阶范围内")
+	}
+	return privateKeyFromScalar(d), nil
+}
+
+// VerifyKey 检查私钥是否对应address，address不区分大小写，大小写混合时还须符合EIP-55校验和
+// 返回私钥实际对应的地址；输入无效时返回错误
+func VerifyKey(privHex, address string) (bool, string, error) {
+	priv, err := ParsePrivateKey(privHex)
+	if err != nil {
+		return false, "", err
+	}
+	claimed, err := Normalize(address)
+	if err != nil {
+		return false, "", err
+	}
+	actual := PrivateKeyToAddress(priv)
+	wipeScalar(priv.D)
+	// 用常量时间比较，不通过比较耗时泄露前几位是否相同
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(claimed)) == 1, actual, nil
+}
+
+// PublicKeys 返回私钥对应的65字节未压缩公钥（0x04 ++ X ++ Y）和33字节压缩公钥
+func PublicKeys(priv *ecdsa.PrivateKey) (uncompressed, compressed []byte) {
+	uncompressed = make([]byte, 65)
+	uncompressed[0] = 0x04
+	priv.X.FillBytes(uncompressed[1:33])
+	priv.Y.FillBytes(uncompressed[33:])
+	return uncompressed, compressPubkey(priv.X, priv.Y)
+}
+
+// PrivateKeyToAddress 从私钥生成以太坊地址
+func PrivateKeyToAddress(priv *ecdsa.PrivateKey) string {
+	return newKeccakHasher().address(priv)
+}
+
+// PrivateKeyToAddressBytes 从私钥生成20字节以太坊地址
+func PrivateKeyToAddressBytes(priv *ecdsa.PrivateKey) [20]byte {
+	return newKeccakHasher().addressBytes(priv)
+}
+
+// address 用k计算私钥对应的以太坊地址
+func (k *keccakHasher) address(priv *ecdsa.PrivateKey) string {
+	return hexAddress(k.addressBytes(priv))
+}
+
+// addressBytes 用k计算私钥对应的20字节以太坊地址
+func (k *keccakHasher) addressBytes(priv *ecdsa.PrivateKey) [20]byte {
+	// X、Y各左补零到32字节，Bytes()会去掉前导零导致地址错误
+	var pubBytes [64]byte
+	priv.X.FillBytes(pubBytes[:32])
+	priv.Y.FillBytes(pubBytes[32:])
+	var address [20]byte
+	copy(address[:], k.sum(pubBytes[:])[12:]) // 取最后20字节
+	return address
+}
+
+// hexAddress 返回带0x前缀的小写十六进制地址
+func hexAddress(a [20]byte) string {
+	var buf [2 + 40]byte
+	copy(buf[:], "0x")
+	hex.Encode(buf[2:], a[:])
+	return string(buf[:])
+}
+
+// AddressTo32Bytes 返回地址左补零到32字节的形式（0x加64位十六进制），即ABI编码和事件topic中的地址
+// 输入可带或不带0x前缀，不区分大小写
+func AddressTo32Bytes(address string) (string, error) {
+	lower, err := Normalize(address)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + strings.Repeat("0", 24) + lower[2:], nil
+}
+
+// ToChecksumAddress 将地址转换为EIP-55校验和格式，输入可带或不带0x前缀
+// 输入不是40位十六进制时返回空字符串
+func ToChecksumAddress(addr string) string {
+	addr = strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")
+	if len(addr) != 40 {
+		return ""
+	}
+	if _, err := hex.DecodeString(addr); err != nil {
+		return ""
+	}
+	lower := strings.ToLower(addr)
+	hash := Keccak256([]byte(lower))
+
+	result := []byte(lower)
+	for i, c := range result {
+		// 对应半字节>=8时字母转大写
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && c <= 'f' && nibble&0x0f >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+// IsValidAddress 检查s是否为带0x前缀的40位十六进制地址
+// 大小写混合时还须符合EIP-55校验和，全小写或全大写视为未带校验和
+func IsValidAddress(s string) bool {
+	if !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	_, err := Normalize(s)
+	return err == nil
+}
+
+// Normalize 校验地址（可不带0x前缀）并返回带0x前缀的小写形式
+func Normalize(s string) (string, error) {
+	body := strings.TrimPrefix(s, "0x")
+	if len(body) != 40 {
+		return "", fmt.Errorf("地址长度应为40位十六进制，实际%d位", len(body))
+	}
+	if _, err := hex.DecodeString(body); err != nil {
+		return "", errors.New("地址包含非十六进制字符")
+	}
+	lower := strings.ToLower(body)
+	if body != lower && body != strings.ToUpper(body) && ToChecksumAddress(body)[2:] != body {
+		return "", errors.New("地址的EIP-55校验和不正确")
+	}
+	return "0x" + lower, nil
+}
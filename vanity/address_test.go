@@ -0,0 +1,162 @@
+package vanity
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPrivateKeyToAddressKnownAnswers(t *testing.T) {
+	tests := []struct {
+		name, key, checksum string
+	}{
+		{"私钥1", "0000000000000000000000000000000000000000000000000000000000000001", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"},
+		{"黄皮书示例", "c85ef7d79691fe79573b1a7064c19c1a9819ebdbd1faaab1a8ec92344438aaf4", "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priv, err := ParsePrivateKey(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			address := PrivateKeyToAddress(priv)
+			if address != strings.ToLower(tt.checksum) {
+				t.Errorf("地址为 %s，期望 %s", address, strings.ToLower(tt.checksum))
+			}
+			if got := ToChecksumAddress(address); got != tt.checksum {
+				t.Errorf("校验和地址为 %s，期望 %s", got, tt.checksum)
+			}
+		})
+	}
+}
+
+func TestFastKeygenOnCurve(t *testing.T) {
+	// secp256k1: y² ≡ x³ + 7 (mod p)，p = 2^256 - 2^32 - 977
+	p := new(big.Int).Lsh(big.NewInt(1), 256)
+	p.Sub(p, new(big.Int).Lsh(big.NewInt(1), 32)).Sub(p, big.NewInt(977))
+	keygen := NewFastKeygen(rand.Reader)
+	for i := 0; i < 50; i++ {
+		priv, err := keygen()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lhs := new(big.Int).Exp(priv.Y, big.NewInt(2), p)
+		rhs := new(big.Int).Exp(priv.X, big.NewInt(3), p)
+		rhs.Add(rhs, big.NewInt(7)).Mod(rhs, p)
+		if lhs.Cmp(rhs) != 0 {
+			t.Fatalf("公钥 (%x, %x) 不在曲线上", priv.X, priv.Y)
+		}
+		if got, want := PrivateKeyToAddress(priv), strings.ToLower(crypto.PubkeyToAddress(priv.PublicKey).Hex()); got != want {
+			t.Fatalf("地址为 %s，go-ethereum为 %s", got, want)
+		}
+	}
+}
+
+func TestAddressShortCoordinates(t *testing.T) {
+	// 从1开始依次找X、Y有前导零字节的私钥，Bytes()会去掉这些零
+	var foundX, foundY bool
+	for d := int64(1); !foundX || !foundY; d++ {
+		priv := privateKeyFromScalar(big.NewInt(d))
+		shortX, shortY := priv.X.BitLen() <= 248, priv.Y.BitLen() <= 248
+		if !(shortX && !foundX) && !(shortY && !foundY) {
+			continue
+		}
+		foundX, foundY = foundX || shortX, foundY || shortY
+		if got, want := PrivateKeyToAddress(priv), strings.ToLower(crypto.PubkeyToAddress(priv.PublicKey).Hex()); got != want {
+			t.Fatalf("私钥 %d 的地址为 %s，go-ethereum为 %s", d, got, want)
+		}
+	}
+}
+
+func TestFastKeygenWeakKey(t *testing.T) {
+	// 依次为0、N、全1（大于N）、1和2，只有2是有效私钥
+	buf := make([]byte, 32*128)
+	curveOrder.FillBytes(buf[32:64])
+	for i := 64; i < 96; i++ {
+		buf[i] = 0xff
+	}
+	buf[127] = 1
+	buf[159] = 2
+	keygen := NewFastKeygen(bytes.NewReader(buf))
+	for i := 0; i < 4; i++ {
+		if priv, err := keygen(); !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("第%d个候选返回 %v, %v，期望 ErrWeakKey", i+1, priv, err)
+		}
+	}
+	priv, err := keygen()
+	if err != nil || priv.D.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("重试后返回 %v, %v，期望私钥2", priv, err)
+	}
+
+	// 随机源一直产生0时重试maxSourceRetries次后停止搜索
+	var retries int
+	g := NewGenerator(Options{Workers: 1, RandSource: func(int) io.Reader { return zeroReader{} },
+		OnSourceError: func(err error) {
+			if errors.Is(err, ErrWeakKey) {
+				retries++
+			}
+		}})
+	if err := g.Run(context.Background(), Pattern{Prefix: "?"}, func(Match) { t.Error("弱私钥不应命中") }); !errors.Is(err, ErrWeakKey) {
+		t.Fatalf("Run返回 %v，期望 ErrWeakKey", err)
+	}
+	if retries != maxSourceRetries {
+		t.Errorf("重试 %d 次，期望 %d", retries, maxSourceRetries)
+	}
+}
+
+// zeroReader 只产生零字节的随机源
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// BenchmarkKeygenFast 由缓冲的随机字节直接构造私钥，即默认的生成路径
+func BenchmarkKeygenFast(b *testing.B) {
+	keygen := NewFastKeygen(rand.Reader)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		priv, err := keygen()
+		if err != nil {
+			b.Fatal(err)
+		}
+		wipeScalar(priv.D)
+	}
+}
+
+// BenchmarkKeygenLegacy ecdsa.GenerateKey，即 -legacy-keygen 的生成路径
+func BenchmarkKeygenLegacy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateKey(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParsePrivateKeyRange(t *testing.T) {
+	n := new(big.Int).Set(curveOrder)
+	for _, tt := range []struct {
+		d  *big.Int
+		ok bool
+	}{
+		{big.NewInt(0), false},
+		{big.NewInt(1), true},
+		{new(big.Int).Sub(n, big.NewInt(1)), true},
+		{n, false},
+	} {
+		key := hex.EncodeToString(tt.d.FillBytes(make([]byte, 32)))
+		if _, err := ParsePrivateKey(key); (err == nil) != tt.ok {
+			t.Errorf("ParsePrivateKey(%s) 错误为 %v，期望有效: %v", key, err, tt.ok)
+		}
+	}
+}
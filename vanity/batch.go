@@ -0,0 +1,73 @@
+package vanity
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchGenerate 不匹配任何模式，用worker并行生成n个随机密钥对，一次性返回（含地址、校验和地址和私钥）
+// 按生成器的配置选择来源，如Options.Mnemonic时同时返回助记词；ctx被取消时返回已生成的部分和ctx.Err()
+func (g *Generator) BatchGenerate(ctx context.Context, n int) ([]Match, error) {
+	g.reset()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make([][]Match, g.opts.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < g.opts.Workers; i++ {
+		quota := workerQuota(int64(n), g.opts.Workers, i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			batches[i] = g.batchWorker(ctx, cancel, i, int(quota))
+		}()
+	}
+	wg.Wait()
+
+	out := make([]Match, 0, n)
+	for _, b := range batches {
+		out = append(out, b...)
+	}
+	if g.err != nil {
+		return out, g.err
+	}
+	return out, ctx.Err()
+}
+
+// batchWorker 生成quota个完整的匹配
+func (g *Generator) batchWorker(ctx context.Context, cancel context.CancelFunc, idx, quota int) []Match {
+	defer g.pinThread()()
+	out := make([]Match, 0, quota)
+	next := g.newSource(idx)
+	failures := 0
+	for len(out) < quota {
+		select {
+		case <-ctx.Done():
+			return out
+		default:
+		}
+		m, err := next()
+		if err != nil {
+			if g.sourceFailed(err, &failures, cancel) {
+				return out
+			}
+			continue
+		}
+		failures = 0
+		atomic.AddInt64(&g.counts[idx].n, 1)
+		m.Attempts = g.Count()
+		g.complete(&m, Pattern{})
+		out = append(out, m)
+	}
+	return out
+}
+
+// workerQuota 将n个任务均分给workers个worker，返回第idx个分得的数量，余数分给前几个
+func workerQuota(n int64, workers, idx int) int64 {
+	quota := n / int64(workers)
+	if int64(idx) < n%int64(workers) {
+		quota++
+	}
+	return quota
+}
@@ -0,0 +1,126 @@
+package vanity
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Scorer 给20字节地址打分，分数越高越好
+type Scorer func(address [20]byte) int
+
+// ScoreLeadingZeros 以前导零半字节个数为分数
+func ScoreLeadingZeros(address [20]byte) int {
+	for i := 0; i < 2*len(address); i++ {
+		if nibbleAt(&address, i) != 0 {
+			return i
+		}
+	}
+	return 2 * len(address)
+}
+
+// scored 带分数的候选
+type scored struct {
+	score int
+	m     Match
+}
+
+// minHeap 按分数排列的最小堆，堆顶为当前最差的候选
+type minHeap []scored
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h minHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x any)        { *h = append(*h, x.(scored)) }
+func (h *minHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Best 一直搜索到ctx结束（如超时），返回score最高的k个地址（k<1时为1），按分数从高到低排列，同分时先找到的在前
+// 每个worker维护自己的k元素最小堆，结束时合并，热循环中不加锁；忽略Options.Count和Forever
+// ctx结束属于正常结束，返回nil错误；随机源持续失败时返回已找到的结果和该错误
+func (g *Generator) Best(ctx context.Context, k int, score Scorer) ([]Match, error) {
+	k = max(k, 1)
+	g.reset()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if g.opts.OnProgress != nil {
+		progressDone := make(chan struct{})
+		defer func() { <-progressDone }()
+		go g.reportProgress(ctx, progressDone)
+	}
+
+	heaps := make([]minHeap, g.opts.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < g.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			heaps[i] = g.bestWorker(ctx, cancel, i, k, score)
+		}()
+	}
+	wg.Wait()
+
+	var all []scored
+	for _, h := range heaps {
+		all = append(all, h...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].score != all[j].score {
+			return all[i].score > all[j].score
+		}
+		return all[i].m.Attempts < all[j].m.Attempts
+	})
+	for _, s := range all[min(k, len(all)):] {
+		clear(s.m.key)
+	}
+	all = all[:min(k, len(all))]
+	out := make([]Match, len(all))
+	for i, s := range all {
+		g.complete(&s.m, Pattern{})
+		out[i] = s.m
+	}
+	return out, g.err
+}
+
+// bestWorker 生成候选并保留分数最高的k个，ctx结束后返回
+func (g *Generator) bestWorker(ctx context.Context, cancel context.CancelFunc, idx, k int, score Scorer) minHeap {
+	defer g.pinThread()()
+	next := g.newSource(idx)
+	h := make(minHeap, 0, k)
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return h
+		default:
+			m, err := next()
+			if err != nil {
+				if g.sourceFailed(err, &failures, cancel) {
+					return h
+				}
+				continue
+			}
+			failures = 0
+			atomic.AddInt64(&g.counts[idx].n, 1)
+
+			s := score(m.raw)
+			if len(h) == k && s <= h[0].score {
+				clear(m.key)
+				continue
+			}
+			m.Attempts = g.Count()
+			if len(h) == k {
+				clear(heap.Pop(&h).(scored).m.key)
+			}
+			heap.Push(&h, scored{s, m.own()})
+			clear(m.key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package vanity
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+)
+
+// Create CREATE合约地址搜索参数，匹配新外部账户在给定nonce部署的合约地址
+type Create struct {
+	Nonce uint64 // 部署交易的nonce，新账户首次部署为0
+}
+
+// CreateAddress 计算CREATE合约地址 keccak256(rlp([sender, nonce]))[12:]
+func CreateAddress(sender [20]byte, nonce uint64) string {
+	return newKeccakHasher().createAddress(sender, nonce)
+}
+
+// createAddress 用k计算CREATE合约地址
+func (k *keccakHasher) createAddress(sender [20]byte, nonce uint64) string {
+	return hexAddress(k.createAddressBytes(sender, nonce))
+}
+
+// createAddressBytes 用k计算20字节CREATE合约地址
+func (k *keccakHasher) createAddressBytes(sender [20]byte, nonce uint64) [20]byte {
+	var address [20]byte
+	copy(address[:], k.sum(rlpSenderNonce(sender, nonce))[12:])
+	return address
+}
+
+// rlpSenderNonce 对[sender, nonce]做最小RLP编码，总长度不超过55字节所以只需短列表形式
+func rlpSenderNonce(sender [20]byte, nonce uint64) []byte {
+	buf := make([]byte, 0, 1+21+9)
+	buf = append(buf, 0) // 列表头，最后回填
+	buf = append(buf, 0x80+20)
+	buf = append(buf, sender[:]...)
+
+	switch {
+	case nonce == 0:
+		buf = append(buf, 0x80) // 0编码为空字符串
+	case nonce < 0x80:
+		buf = append(buf, byte(nonce)) // 单字节直接编码
+	default:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], nonce)
+		i := 0
+		for b[i] == 0 {
+			i++
+		}
+		buf = append(buf, 0x80+byte(8-i))
+		buf = append(buf, b[i:]...)
+	}
+	buf[0] = 0xc0 + byte(len(buf)-1)
+	return buf
+}
+
+// source 返回用keygen生成随机外部账户并计算其部署合约地址的候选生成函数
+func (c *Create) source(keygen func() (*ecdsa.PrivateKey, error)) candidateSource {
+	next := eoaKeys(keygen)
+	h := newKeccakHasher()
+	var sender [20]byte
+	return func() (Match, error) {
+		var key []byte
+		var err error
+		sender, key, err = next()
+		if err != nil {
+			return Match{}, err
+		}
+		return Match{raw: h.createAddressBytes(sender, c.Nonce), sender: &sender, key: key}, nil
+	}
+}
@@ -0,0 +1,54 @@
+package vanity
+
+import (
+	"encoding/binary"
+)
+
+// Create2 CREATE2盐搜索的部署参数
+type Create2 struct {
+	Deployer     [20]byte // 部署者（工厂合约）地址
+	InitCodeHash [32]byte // 合约初始化代码的keccak256
+
+	// Shard/Shards 多机分片：只尝试 salt % Shards == Shard 的盐，Shards<=1时不分片
+	Shard, Shards uint64
+}
+
+// Create2Address 计算CREATE2合约地址 keccak256(0xff ++ deployer ++ salt ++ initCodeHash)[12:]
+func Create2Address(deployer [20]byte, salt, initCodeHash [32]byte) string {
+	return newKeccakHasher().create2Address(deployer, salt, initCodeHash)
+}
+
+// create2Address 用k计算CREATE2合约地址
+func (k *keccakHasher) create2Address(deployer [20]byte, salt, initCodeHash [32]byte) string {
+	return hexAddress(k.create2AddressBytes(&deployer, &salt, &initCodeHash))
+}
+
+// create2Marker CREATE2地址哈希输入的首字节
+var create2Marker = []byte{0xff}
+
+// create2AddressBytes 用k计算20字节CREATE2合约地址，各段依次写入哈希，不拼接到临时缓冲
+// 参数用指针，搜索时直接引用部署参数和盐计数器，不为每个候选复制和分配
+func (k *keccakHasher) create2AddressBytes(deployer *[20]byte, salt, initCodeHash *[32]byte) [20]byte {
+	var address [20]byte
+	copy(address[:], k.sum(create2Marker, deployer[:], salt[:], initCodeHash[:])[12:])
+	return address
+}
+
+// workerSource 返回n个worker中第idx个的候选生成函数，各worker在本分片内交错遍历盐
+func (c *Create2) workerSource(idx, n uint64) candidateSource {
+	shards := max(c.Shards, 1)
+	return c.source(c.Shard+idx*shards, n*shards)
+}
+
+// source 返回按序遍历盐的候选生成函数，第start个worker依次尝试 start, start+step, ...
+// 盐为大端序计数器，只使用低8字节
+func (c *Create2) source(start, step uint64) candidateSource {
+	next := start
+	h := newKeccakHasher()
+	var salt [32]byte
+	return func() (Match, error) {
+		binary.BigEndian.PutUint64(salt[24:], next)
+		next += step
+		return Match{raw: h.create2AddressBytes(&c.Deployer, &salt, &c.InitCodeHash), salt: &salt}, nil
+	}
+}
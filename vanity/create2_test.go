@@ -0,0 +1,51 @@
+package vanity
+
+import "testing"
+
+// create2AddressConcat 先拼接到临时缓冲再求哈希的CREATE2地址，即改为分段写入之前的做法，作为对照
+func create2AddressConcat(k *keccakHasher, deployer [20]byte, salt, initCodeHash [32]byte) [20]byte {
+	var buf [1 + 20 + 32 + 32]byte
+	buf[0] = 0xff
+	copy(buf[1:21], deployer[:])
+	copy(buf[21:53], salt[:])
+	copy(buf[53:], initCodeHash[:])
+	var address [20]byte
+	copy(address[:], k.sum(buf[:])[12:])
+	return address
+}
+
+func TestCreate2AddressChunks(t *testing.T) {
+	k := newKeccakHasher()
+	var deployer [20]byte
+	var salt, initCodeHash [32]byte
+	for i := range 8 {
+		deployer[i], salt[31-i], initCodeHash[i*2] = byte(i+1), byte(i*7), byte(0xff-i)
+		want := create2AddressConcat(k, deployer, salt, initCodeHash)
+		if got := k.create2AddressBytes(&deployer, &salt, &initCodeHash); got != want {
+			t.Fatalf("分段写入得到 %x，拼接得到 %x", got, want)
+		}
+	}
+}
+
+// BenchmarkCreate2Address 对比CREATE2地址先拼接再哈希与分段写入哈希器
+func BenchmarkCreate2Address(b *testing.B) {
+	k := newKeccakHasher()
+	var deployer [20]byte
+	var salt, initCodeHash [32]byte
+	var sink [20]byte
+	b.Run("concat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			salt[31] = byte(i)
+			sink = create2AddressConcat(k, deployer, salt, initCodeHash)
+		}
+	})
+	b.Run("chunks", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			salt[31] = byte(i)
+			sink = k.create2AddressBytes(&deployer, &salt, &initCodeHash)
+		}
+	})
+	_ = sink
+}
@@ -0,0 +1,148 @@
+package vanity
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Distribution 按生成器的配置生成n个地址（不做匹配），返回各首位半字节0-f出现的次数，用于检查生成路径是否均匀
+// 私钥补零错误等问题会让首位明显偏向0；ctx被取消时返回已统计的部分和ctx.Err()
+func (g *Generator) Distribution(ctx context.Context, n int64) ([16]int64, error) {
+	g.reset()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if g.opts.OnProgress != nil {
+		progressDone := make(chan struct{})
+		defer func() { <-progressDone }()
+		go g.reportProgress(ctx, progressDone)
+	}
+
+	var counts [16]int64
+	var wg sync.WaitGroup
+	for i := 0; i < g.opts.Workers; i++ {
+		quota := workerQuota(n, g.opts.Workers, i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := g.distributionWorker(ctx, cancel, i, quota)
+			for d, c := range local {
+				atomic.AddInt64(&counts[d], c)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if g.err != nil {
+		return counts, g.err
+	}
+	return counts, ctx.Err()
+}
+
+// distributionWorker 生成quota个候选并统计首位半字节
+func (g *Generator) distributionWorker(ctx context.Context, cancel context.CancelFunc, idx int, quota int64) [16]int64 {
+	defer g.pinThread()()
+	var counts [16]int64
+	next := g.newSource(idx)
+	failures := 0
+	for done := int64(0); done < quota; {
+		select {
+		case <-ctx.Done():
+			return counts
+		default:
+		}
+		m, err := next()
+		if err != nil {
+			if g.sourceFailed(err, &failures, cancel) {
+				return counts
+			}
+			continue
+		}
+		failures = 0
+		clear(m.key)
+		counts[m.raw[0]>>4]++
+		done++
+		atomic.AddInt64(&g.counts[idx].n, 1)
+	}
+	return counts
+}
+
+// CountMatches 按生成器的配置生成attempts个候选，只统计其中匹配p的个数，不输出任何私钥，用于实测模式难度
+// 每个候选检查后立即清零私钥；ctx被取消时返回已统计的部分和ctx.Err()，模式无效时返回Validate的错误
+func (g *Generator) CountMatches(ctx context.Context, p Pattern, attempts int64) (int64, error) {
+	g.reset()
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	p = p.normalize()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if g.opts.OnProgress != nil {
+		progressDone := make(chan struct{})
+		defer func() { <-progressDone }()
+		go g.reportProgress(ctx, progressDone)
+	}
+
+	// 命中数单独计数，不影响Matches，也不与Run的名次计数混用
+	var matched int64
+	var wg sync.WaitGroup
+	for i := 0; i < g.opts.Workers; i++ {
+		quota := workerQuota(attempts, g.opts.Workers, i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer g.pinThread()()
+			matcher := newRawMatcher(p)
+			next := g.newSource(i)
+			failures := 0
+			for done := int64(0); done < quota; {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				m, err := next()
+				if err != nil {
+					if g.sourceFailed(err, &failures, cancel) {
+						return
+					}
+					continue
+				}
+				failures = 0
+				hit := matcher.match(&m)
+				clear(m.key)
+				if hit {
+					atomic.AddInt64(&matched, 1)
+				}
+				done++
+				atomic.AddInt64(&g.counts[i].n, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if g.err != nil {
+		return matched, g.err
+	}
+	return matched, ctx.Err()
+}
+
+// ChiSquare 返回各类计数相对均匀分布的卡方统计量，自由度为len(counts)-1
+func ChiSquare(counts []int64) float64 {
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	expected := float64(total) / float64(len(counts))
+	var chi float64
+	for _, c := range counts {
+		chi += math.Pow(float64(c)-expected, 2) / expected
+	}
+	return chi
+}
@@ -0,0 +1,21 @@
+package vanity
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCountMatchesLocal(t *testing.T) {
+	g := NewGenerator(WithWorkers(2))
+	// 通配符前缀匹配任意地址
+	n, err := g.CountMatches(context.Background(), Pattern{Prefix: "?"}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 100 || g.Count() != 100 {
+		t.Fatalf("CountMatches() = %d，Count() = %d，期望都为 100", n, g.Count())
+	}
+	if g.Matches() != 0 {
+		t.Fatalf("CountMatches之后Matches() = %d，期望 0", g.Matches())
+	}
+}
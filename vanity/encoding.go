@@ -0,0 +1,92 @@
+package vanity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// textEncoding 以太坊地址之外的地址编码，模式匹配整个编码后的字符串
+type textEncoding struct {
+	name     string
+	alphabet string                      // 可能出现的字符
+	head     string                      // 所有地址共同的开头，模式前缀须以它开头
+	encode   func(address string) string // 由带0x前缀的以太坊地址编码
+}
+
+var (
+	tronEncoding = &textEncoding{name: "Tron", alphabet: base58Alphabet, head: "T", encode: TronAddress}
+	icapEncoding = &textEncoding{name: "ICAP", alphabet: "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ", head: "XE", encode: ICAPAddress}
+)
+
+// encoding 返回模式使用的地址编码，以太坊地址本身返回nil
+func (p Pattern) encoding() *textEncoding {
+	switch {
+	case p.Tron:
+		return tronEncoding
+	case p.ICAP:
+		return icapEncoding
+	}
+	return nil
+}
+
+// validateEncoded 检查编码地址模式，只支持前缀、后缀和正则，前缀须包含共同的开头
+func (p Pattern) validateEncoded(e *textEncoding) error {
+	if p.Tron && p.ICAP {
+		return errors.New("Tron与ICAP模式不能同时指定")
+	}
+	if p.SuffixInt != nil || p.Contains != "" || p.Target != "" || p.MinUpper != 0 || p.Zeros != 0 || p.ZeroBytes != 0 || p.Palindrome != 0 || p.Charset != "" || p.Repeat != 0 || p.Any != nil || p.Checksum {
+		return fmt.Errorf("%s模式只支持前缀、后缀和正则", e.name)
+	}
+	head, prefix := e.head, p.Prefix
+	if p.IgnoreCase {
+		head, prefix = strings.ToLower(head), strings.ToLower(prefix)
+	}
+	if prefix != "" && !strings.HasPrefix(prefix, head[:min(len(head), len(prefix))]) {
+		return fmt.Errorf("%s地址以%s开头，前缀应包含开头的%s", e.name, e.head, e.head)
+	}
+	alphabet := e.alphabet
+	if p.IgnoreCase {
+		alphabet += strings.ToLower(alphabet) + strings.ToUpper(alphabet)
+	}
+	for _, part := range []struct{ name, s string }{{"前缀", p.Prefix}, {"后缀", p.Suffix}} {
+		for _, c := range part.s {
+			if c != wildcard && !strings.ContainsRune(alphabet, c) {
+				return fmt.Errorf("%s包含%s地址中不会出现的字符 %q", part.name, e.name, c)
+			}
+		}
+	}
+	return nil
+}
+
+// encodedDifficulty 返回编码地址模式的期望尝试次数：共同开头固定，其余每位按字母表均匀估算，
+// 忽略大小写时按大小写变体数计
+func (p Pattern) encodedDifficulty(e *textEncoding) float64 {
+	rest := p.Prefix[min(len(e.head), len(p.Prefix)):]
+	d := 1.0
+	for _, c := range rest + p.Suffix {
+		if c == wildcard {
+			continue
+		}
+		n := 1
+		if p.IgnoreCase {
+			n = strings.Count(strings.ToLower(e.alphabet), strings.ToLower(string(c)))
+		}
+		d *= float64(len(e.alphabet)) / float64(n)
+	}
+	return d
+}
+
+// matchEncoded 检查编码后的地址是否匹配前缀、后缀和正则
+func (p Pattern) matchEncoded(address string) bool {
+	if len(address) < len(p.Prefix)+len(p.Suffix) {
+		return false
+	}
+	if p.IgnoreCase {
+		address = strings.ToLower(address)
+	}
+	if !matchWildcard(address[:len(p.Prefix)], p.Prefix) || !matchWildcard(address[len(address)-len(p.Suffix):], p.Suffix) {
+		return false
+	}
+	return p.Regex == nil || p.Regex.MatchString(address)
+}
@@ -0,0 +1,58 @@
+package vanity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	"time"
+)
+
+// SampleDifficulty 用随机地址蒙特卡洛估算模式的期望尝试次数，适用于正则等无法直接计算的模式
+// 返回估算值和命中次数，未命中或模式无效时估算值为0
+func SampleDifficulty(p Pattern, samples int) (float64, int) {
+	if p.Validate() != nil {
+		return 0, 0
+	}
+	var raw [20]byte
+	buf := make([]byte, 2+40)
+	copy(buf, "0x")
+	hits := 0
+	p = p.normalize()
+	for i := 0; i < samples; i++ {
+		rand.Read(raw[:])
+		hex.Encode(buf[2:], raw[:])
+		if p.match(string(buf)) {
+			hits++
+		}
+	}
+	if hits == 0 {
+		return 0, 0
+	}
+	return float64(samples) / float64(hits), hits
+}
+
+// Benchmark 按opts以空模式运行d时长（或直到ctx取消），返回生成的地址数和实际用时
+// 走与搜索相同的生成路径，只是不会命中
+func Benchmark(ctx context.Context, opts Options, d time.Duration) (int64, time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	g := NewGenerator(opts)
+	g.Run(ctx, Pattern{}, func(Match) {})
+	return g.Count(), g.Elapsed()
+}
+
+// MeasureRate 按opts以空模式运行d时长，返回每秒生成地址数
+func MeasureRate(ctx context.Context, opts Options, d time.Duration) float64 {
+	count, elapsed := Benchmark(ctx, opts, d)
+	return float64(count) / elapsed.Seconds()
+}
+
+// AttemptsForProbability 返回期望尝试次数为d的模式以prob概率（0到1之间）找到至少一个匹配所需的尝试次数
+// 每次尝试独立命中，所需次数服从几何分布：n = ln(1-prob) / ln(1-1/d)，d很大时约为 -d·ln(1-prob)
+func AttemptsForProbability(d, prob float64) float64 {
+	if d <= 1 {
+		return 1
+	}
+	return math.Log1p(-prob) / math.Log1p(-1/d)
+}
@@ -0,0 +1,20 @@
+package vanity_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+func ExampleGenerator_Run() {
+	g := vanity.NewGenerator(vanity.WithCount(1))
+	err := g.Run(context.Background(), vanity.Pattern{Prefix: "a"}, func(m vanity.Match) {
+		fmt.Println(strings.HasPrefix(m.Address, "0xa"), len(m.PrivateKey))
+	})
+	fmt.Println(err, g.Matches())
+	// Output:
+	// true 64
+	// <nil> 1
+}
@@ -0,0 +1,497 @@
+package vanity
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Match 匹配到的地址及对应私钥
+type Match struct {
+	Address         string // 小写地址，带0x前缀
+	ChecksumAddress string // EIP-55校验和地址
+	PrivateKey      string // 64位十六进制私钥，CREATE2模式下为空
+	Salt            string // CREATE2模式下命中的盐，带0x前缀
+	Sender          string // CREATE模式下部署合约的外部账户地址，Address为合约地址
+	Mnemonic        string // 助记词模式下派生出私钥的BIP-39助记词
+	Path            string // HD索引模式下命中的完整派生路径，如 m/44'/60'/0'/0/123
+	PublicKey       string // Options.WithPubkey时的65字节未压缩公钥，带0x前缀
+	CompressedKey   string // Options.WithPubkey时的33字节压缩公钥，带0x前缀
+	Attempts        int64  // 找到时的总尝试次数
+	LeadingZeros    int    // 地址前导零半字节个数
+	RepeatRun       int    // Pattern.Repeat模式下命中的连续相同字符个数
+	MatchedPattern  string // Pattern.Any模式下命中的模式
+	ContainsAt      int    // Pattern.Contains模式下子串在不含0x的地址中的起始位置（从0开始），其他模式为-1
+	Distance        int    // Pattern.Target模式下与目标地址的汉明距离，其他模式为-1
+	Uppercase       int    // Pattern.MinUpper模式下校验和地址中的大写字母数
+	Tron            string // Pattern.Tron模式下的Tron base58check地址
+	ICAP            string // Pattern.ICAP模式下的ICAP地址
+
+	// 以下为候选的原始字节，指向来源的缓冲区，worker只在命中时编码为对应字符串字段
+	key    []byte    // 私钥的32字节标量，worker用完即清零
+	raw    [20]byte  // 地址
+	sender *[20]byte // CREATE模式的部署账户
+	salt   *[32]byte // CREATE2模式的盐
+	hd     *HDIndex  // HD索引模式的父节点，与index一起格式化为Path
+	index  uint32    // HD索引模式的子索引
+}
+
+// Options 生成器配置
+type Options struct {
+	Workers int   // worker数量，<=0时使用CPU核心数
+	Count   int64 // 需要找到的匹配数量，<=0时为1
+	Forever bool  // 为true时忽略Count，一直搜索直到ctx被取消
+
+	// Create2 非nil时改为搜索CREATE2盐，匹配部署出的合约地址
+	Create2 *Create2
+	// Create 非nil时匹配随机外部账户以Create.Nonce部署出的合约地址
+	Create *Create
+	// Mnemonic 非nil时候选私钥由随机BIP-39助记词派生
+	Mnemonic *Mnemonic
+	// HDIndex 非nil时在已有助记词的派生空间中按索引搜索，由NewHDIndex创建
+	HDIndex *HDIndex
+
+	// LegacyKeygen 为true时用ecdsa.GenerateKey生成私钥，用于与快速路径对照正确性，此时忽略RandSource
+	LegacyKeygen bool
+	// RandSource 非nil时为第worker个worker返回随机源，nil时使用crypto/rand
+	// 仅用于测试和可复现的基准：配合NewSeededReader时私钥完全可预测，绝不能用于真实资产
+	RandSource func(worker int) io.Reader
+	// WithPubkey 为true时在匹配中附带未压缩和压缩公钥
+	WithPubkey bool
+	// LockOSThread 为true时每个worker用runtime.LockOSThread固定在独占的系统线程上，
+	// 线程数与worker数一致、便于按线程观察和限制CPU，但不设置CPU亲和性，调度器也无法再复用这些线程
+	LockOSThread bool
+
+	// OnSourceError 非nil时在候选生成偶发失败并重试时调用，如随机源产生了ErrWeakKey，可能并发调用
+	OnSourceError func(err error)
+
+	// OnProgress 非nil时每隔ProgressInterval以当前尝试次数和用时调用
+	OnProgress       func(count int64, elapsed time.Duration)
+	ProgressInterval time.Duration // <=0时为100ms
+
+	// Pattern Search搜索的模式，Run和Stream使用各自传入的模式
+	Pattern Pattern
+	// Output 非nil时Run每个匹配另向其写一行"校验和地址 私钥"（CREATE2模式为盐），nil时不写；写入失败时停止搜索
+	Output io.Writer
+	// Seen 非nil时Run对每个命中的地址（带0x的小写形式）调用，返回true表示重复，该匹配被丢弃且不计入Count，
+	// 搜索继续直到找到Count个不重复的匹配；各worker的调用已串行化
+	Seen func(address string) bool
+
+	checksum bool // WithChecksumMatch设置，Search时按EIP-55校验和匹配
+}
+
+// workerCount 单个worker的尝试计数，补齐到64字节独占缓存行
+type workerCount struct {
+	n int64
+	_ [56]byte
+}
+
+// Generator 多协程地址生成器，可以依次多次搜索，每次搜索重新计数，但不能并发搜索
+type Generator struct {
+	opts    Options
+	start   time.Time
+	counts  []workerCount // 每个worker独立计数，避免共享计数器的缓存行争用
+	matches int64
+	mu      sync.Mutex // 串行化onMatch回调
+	errOnce sync.Once
+	err     error // 导致搜索停止的致命错误
+
+	rejected func(key []byte) // 测试钩子，未命中的候选清零私钥后以其缓冲调用
+}
+
+// NewGenerator 按顺序应用配置项创建生成器，可直接传入Options或With开头的配置项
+// 默认worker数为CPU核心数，Count为1，不写Output
+func NewGenerator(options ...Option) *Generator {
+	var opts Options
+	for _, o := range options {
+		o.apply(&opts)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Count <= 0 {
+		opts.Count = 1
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = 100 * time.Millisecond
+	}
+	return &Generator{opts: opts, counts: make([]workerCount, opts.Workers)}
+}
+
+// Workers 返回实际使用的worker数量
+func (g *Generator) Workers() int { return g.opts.Workers }
+
+// Count 返回已生成的地址总数，为各worker计数之和
+func (g *Generator) Count() int64 {
+	var total int64
+	for i := range g.counts {
+		total += atomic.LoadInt64(&g.counts[i].n)
+	}
+	return total
+}
+
+// WorkerCounts 返回每个worker已生成的地址数，下标为worker编号，用于检查各worker是否调度均匀
+func (g *Generator) WorkerCounts() []int64 {
+	counts := make([]int64, len(g.counts))
+	for i := range g.counts {
+		counts[i] = atomic.LoadInt64(&g.counts[i].n)
+	}
+	return counts
+}
+
+// Matches 返回已找到的匹配数量
+func (g *Generator) Matches() int64 {
+	if n := atomic.LoadInt64(&g.matches); n < g.opts.Count || g.opts.Forever {
+		return n
+	}
+	return g.opts.Count
+}
+
+// reset 清空上一次搜索的计数和错误，同一生成器可以依次多次搜索，但不能并发搜索
+func (g *Generator) reset() {
+	g.start = time.Now()
+	for i := range g.counts {
+		atomic.StoreInt64(&g.counts[i].n, 0)
+	}
+	atomic.StoreInt64(&g.matches, 0)
+	g.errOnce = sync.Once{}
+	g.err = nil
+}
+
+// Elapsed 返回自Run开始的用时
+func (g *Generator) Elapsed() time.Duration { return time.Since(g.start) }
+
+// Run 启动worker搜索匹配p的地址，每个匹配串行调用onMatch
+// onMatch在找到匹配的worker中同步调用，回调阻塞时其余worker找到下一个匹配后也随之等待，
+// 输出跟不上时搜索变慢，匹配不会在内存中积压
+// 找到Options.Count个匹配后返回nil，ctx被取消时返回ctx.Err()，随机源持续失败时返回该错误
+// Options.Forever时只在ctx取消或出错时返回；模式无效时不启动worker，直接返回Validate的错误，空模式只生成不命中
+func (g *Generator) Run(ctx context.Context, p Pattern, onMatch func(Match)) error {
+	g.reset()
+	if err := p.Validate(); err != nil && !errors.Is(err, ErrEmptyPattern) {
+		return err
+	}
+	p = p.normalize()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if g.opts.OnProgress != nil {
+		progressDone := make(chan struct{})
+		defer func() { <-progressDone }()
+		go g.reportProgress(ctx, progressDone)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < g.opts.Workers; i++ {
+		wg.Add(1)
+		go g.worker(ctx, cancel, i, p, newRawMatcher(p), onMatch, &wg)
+	}
+
+	// 等待全部worker退出，确保已开始的onMatch都已完成
+	wg.Wait()
+
+	if g.err != nil {
+		return g.err
+	}
+	if !g.opts.Forever && atomic.LoadInt64(&g.matches) >= g.opts.Count {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// reportProgress 定时调用OnProgress，ctx结束后关闭done
+func (g *Generator) reportProgress(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(g.opts.ProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.opts.OnProgress(g.Count(), g.Elapsed())
+		}
+	}
+}
+
+// Search 搜索WithPattern（Options.Pattern）配置的模式，其余同Run
+func (g *Generator) Search(ctx context.Context, onMatch func(Match)) error {
+	p := g.opts.Pattern
+	if g.opts.checksum {
+		p.Checksum = true
+	}
+	return g.Run(ctx, p, onMatch)
+}
+
+// Stream 在后台搜索匹配p的地址，通过返回的channel逐个发送匹配
+// 找到Options.Count个匹配（Options.Forever时不限）或ctx被取消后channel关闭，调用方应读到关闭或取消ctx
+// channel关闭后调用返回的err得到Run的返回值，如模式无效或随机源出错；关闭前调用会阻塞到搜索结束
+func (g *Generator) Stream(ctx context.Context, p Pattern) (<-chan Match, func() error) {
+	ch := make(chan Match)
+	done := make(chan struct{})
+	var runErr error
+	go func() {
+		defer close(ch)
+		defer close(done)
+		runErr = g.Run(ctx, p, func(m Match) {
+			select {
+			case ch <- m:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return ch, func() error {
+		<-done
+		return runErr
+	}
+}
+
+// worker 工作协程，生成候选地址并检查模式，找到Count个匹配后调用cancel
+func (g *Generator) worker(ctx context.Context, cancel context.CancelFunc, idx int, p Pattern, matcher *rawMatcher, onMatch func(Match), wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer g.pinThread()()
+
+	next := g.newSource(idx)
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			m, err := next()
+			if err != nil {
+				if g.sourceFailed(err, &failures, cancel) {
+					return
+				}
+				continue
+			}
+			failures = 0
+			atomic.AddInt64(&g.counts[idx].n, 1)
+
+			if !matcher.match(&m) {
+				clear(m.key)
+				if g.rejected != nil {
+					g.rejected(m.key)
+				}
+				continue
+			}
+			if g.opts.Seen != nil && g.seen(m.raw) {
+				clear(m.key)
+				continue
+			}
+
+			// 原子计数决定名次，超出Count的匹配直接丢弃
+			n := atomic.AddInt64(&g.matches, 1)
+			if n > g.opts.Count && !g.opts.Forever {
+				clear(m.key)
+				return
+			}
+			m.Attempts = g.Count()
+			g.complete(&m, p)
+			g.mu.Lock()
+			onMatch(m)
+			err = g.writeOutput(m)
+			g.mu.Unlock()
+			if err != nil {
+				g.fail(fmt.Errorf("写入匹配失败: %w", err))
+				cancel()
+				return
+			}
+			if n == g.opts.Count && !g.opts.Forever {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// seen 串行调用Options.Seen判断地址是否重复
+func (g *Generator) seen(raw [20]byte) bool {
+	address := hexAddress(raw)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.opts.Seen(address)
+}
+
+// writeOutput 向Options.Output写一行匹配结果，调用方须持有g.mu
+func (g *Generator) writeOutput(m Match) error {
+	if g.opts.Output == nil {
+		return nil
+	}
+	secret := m.PrivateKey
+	if m.Salt != "" {
+		secret = m.Salt
+	}
+	_, err := fmt.Fprintln(g.opts.Output, m.ChecksumAddress, secret)
+	return err
+}
+
+// complete 将命中候选的原始字节编码为字符串字段，填写校验和地址、私钥等，并清零私钥缓冲
+func (g *Generator) complete(m *Match, p Pattern) {
+	m.Address = hexAddress(m.raw)
+	if m.sender != nil {
+		m.Sender = hexAddress(*m.sender)
+		m.sender = nil
+	}
+	if m.salt != nil {
+		m.Salt = "0x" + hex.EncodeToString(m.salt[:])
+		m.salt = nil
+	}
+	if m.hd != nil {
+		m.Path = fmt.Sprintf("%s/%d", FormatPath(m.hd.Parent), m.index)
+		m.hd = nil
+	}
+	m.ChecksumAddress = ToChecksumAddress(m.Address)
+	m.LeadingZeros = LeadingZeros(m.Address)
+	if p.Tron {
+		m.Tron = TronAddress(m.Address)
+	}
+	if p.ICAP {
+		m.ICAP = ICAPAddress(m.Address)
+	}
+	if p.Repeat > 0 {
+		m.RepeatRun = RunLength(p.target(m.Address), p.RepeatEnd)
+	}
+	if p.Any != nil {
+		m.MatchedPattern, _ = p.Any.lookup(p.target(m.Address))
+	}
+	m.ContainsAt, m.Distance = -1, -1
+	if p.Target != "" {
+		m.Distance = HammingDistance(m.Address, p.Target)
+	}
+	if p.MinUpper > 0 {
+		m.Uppercase = CountUpper(m.ChecksumAddress)
+	}
+	if p.Contains != "" {
+		m.ContainsAt = strings.Index(p.target(m.Address), p.Contains)
+	}
+	if m.key != nil {
+		m.PrivateKey = hex.EncodeToString(m.key)
+		if g.opts.WithPubkey {
+			priv := privateKeyFromScalar(new(big.Int).SetBytes(m.key))
+			uncompressed, compressed := PublicKeys(priv)
+			wipeScalar(priv.D)
+			m.PublicKey = "0x" + hex.EncodeToString(uncompressed)
+			m.CompressedKey = "0x" + hex.EncodeToString(compressed)
+		}
+		clear(m.key)
+		m.key = nil
+	}
+}
+
+// own 复制候选中指向来源缓冲区的字节，使其在来源下次生成后仍然有效
+func (m Match) own() Match {
+	if m.key != nil {
+		m.key = append([]byte(nil), m.key...)
+	}
+	if m.sender != nil {
+		sender := *m.sender
+		m.sender = &sender
+	}
+	if m.salt != nil {
+		salt := *m.salt
+		m.salt = &salt
+	}
+	return m
+}
+
+// maxSourceRetries 候选生成连续失败的最大重试次数
+const maxSourceRetries = 3
+
+// candidateSource 候选生成函数，每次调用产生一个只填了原始字节（及助记词）的Match
+// 私钥只放在Match.key中，由worker在命中时编码为PrivateKey，下次调用前可能被覆盖
+type candidateSource func() (Match, error)
+
+// pinThread Options.LockOSThread时将当前goroutine固定到独占的系统线程，返回解除固定的函数
+func (g *Generator) pinThread() func() {
+	if !g.opts.LockOSThread {
+		return func() {}
+	}
+	runtime.LockOSThread()
+	return runtime.UnlockOSThread
+}
+
+// sourceFailed 处理候选生成失败：偶发失败时通知OnSourceError后重试，连续失败超过maxSourceRetries次则记录错误并停止整个搜索
+// 返回worker是否应退出；绝不使用生成失败的私钥
+func (g *Generator) sourceFailed(err error, failures *int, cancel context.CancelFunc) bool {
+	if *failures++; *failures > maxSourceRetries {
+		g.fail(fmt.Errorf("生成候选地址失败: %w", err))
+		cancel()
+		return true
+	}
+	if g.opts.OnSourceError != nil {
+		g.opts.OnSourceError(err)
+	}
+	return false
+}
+
+// fail 记录第一个致命错误，Run结束时返回
+func (g *Generator) fail(err error) {
+	g.errOnce.Do(func() { g.err = err })
+}
+
+// newSource 返回第idx个worker的候选生成函数
+func (g *Generator) newSource(idx int) candidateSource {
+	if g.opts.Create2 != nil {
+		return g.opts.Create2.workerSource(uint64(idx), uint64(g.opts.Workers))
+	}
+	if g.opts.HDIndex != nil {
+		return g.opts.HDIndex.source(uint64(idx), uint64(g.opts.Workers))
+	}
+	var r io.Reader = rand.Reader
+	if g.opts.RandSource != nil {
+		r = g.opts.RandSource(idx)
+	}
+	if g.opts.Mnemonic != nil {
+		return g.opts.Mnemonic.source(r)
+	}
+	// 快速路径的缓冲随机源不是并发安全的，每个worker各用一个
+	keygen := GenerateKey
+	if !g.opts.LegacyKeygen {
+		keygen = NewFastKeygen(r)
+	}
+	if g.opts.Create != nil {
+		return g.opts.Create.source(keygen)
+	}
+	return eoaSource(keygen)
+}
+
+// eoaSource 返回用keygen生成随机私钥及其外部账户地址的候选生成函数
+func eoaSource(keygen func() (*ecdsa.PrivateKey, error)) candidateSource {
+	next := eoaKeys(keygen)
+	return func() (Match, error) {
+		address, key, err := next()
+		if err != nil {
+			return Match{}, err
+		}
+		return Match{raw: address, key: key}, nil
+	}
+}
+
+// eoaKeys 返回用keygen生成随机私钥及其20字节外部账户地址的函数
+// 私钥写入返回的32字节缓冲，下次调用时覆盖，big.Int形式的私钥用后即清零
+func eoaKeys(keygen func() (*ecdsa.PrivateKey, error)) func() ([20]byte, []byte, error) {
+	h := newKeccakHasher()
+	var privBytes [32]byte
+	return func() ([20]byte, []byte, error) {
+		// 生成私钥
+		privKey, err := keygen()
+		if err != nil {
+			return [20]byte{}, nil, err
+		}
+		privKey.D.FillBytes(privBytes[:]) // 左补零，保证64个十六进制字符
+		address := h.addressBytes(privKey)
+		wipeScalar(privKey.D)
+		return address, privBytes[:], nil
+	}
+}
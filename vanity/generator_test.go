@@ -0,0 +1,327 @@
+package vanity
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestGeneratorReuse(t *testing.T) {
+	g := NewGenerator(WithWorkers(2), WithCount(2))
+	p := Pattern{Prefix: "a"}
+	for i := 0; i < 2; i++ {
+		var found int
+		if err := g.Run(context.Background(), p, func(m Match) {
+			found++
+			if !strings.HasPrefix(m.Address, "0xa") {
+				t.Errorf("地址 %s 不匹配前缀", m.Address)
+			}
+		}); err != nil {
+			t.Fatalf("第%d次Run: %v", i+1, err)
+		}
+		if found != 2 || g.Matches() != 2 {
+			t.Fatalf("第%d次Run找到 %d 个，Matches() = %d，期望 2", i+1, found, g.Matches())
+		}
+	}
+
+	var streamed int
+	matches, streamErr := g.Stream(context.Background(), p)
+	for range matches {
+		streamed++
+	}
+	if streamed != 2 || streamErr() != nil {
+		t.Fatalf("Run之后Stream找到 %d 个，错误 %v，期望 2 个", streamed, streamErr())
+	}
+
+	if n, err := g.CountMatches(context.Background(), p, 64); err != nil || n > 64 {
+		t.Fatalf("CountMatches() = %d, %v", n, err)
+	}
+	if err := g.Run(context.Background(), p, func(Match) {}); err != nil || g.Matches() != 2 {
+		t.Fatalf("CountMatches之后Run: %v，Matches() = %d", err, g.Matches())
+	}
+}
+
+func TestRunInvalidPattern(t *testing.T) {
+	g := NewGenerator(WithWorkers(1))
+	for _, p := range []Pattern{
+		{Prefix: strings.Repeat("a", 41)},
+		{Prefix: strings.Repeat("a", 30), ZeroBytes: 6},
+		{Zeros: 41},
+		{Palindrome: 21},
+		{Prefix: "xyz"},
+		{Zeros: 2, Prefix: "ab"},
+	} {
+		if err := g.Run(context.Background(), p, func(Match) { t.Errorf("模式 %v 不应命中", p) }); err == nil {
+			t.Errorf("模式 %v: Run未返回错误", p)
+		}
+		if p.Match("0x" + strings.Repeat("0", 40)) {
+			t.Errorf("模式 %v: Match返回true", p)
+		}
+	}
+	if (Pattern{Prefix: "a"}).Match("0xa") {
+		t.Error("长度不对的地址不应匹配")
+	}
+}
+
+func TestWithOutput(t *testing.T) {
+	var buf bytes.Buffer
+	g := NewGenerator(WithWorkers(1), WithCount(2), WithOutput(&buf))
+	var want []string
+	if err := g.Run(context.Background(), Pattern{Prefix: "a"}, func(m Match) {
+		want = append(want, m.ChecksumAddress+" "+m.PrivateKey)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Output写入 %q，期望 %q", got, want)
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	matches, err := NewGenerator(WithWorkers(1)).Stream(context.Background(), Pattern{Prefix: "xyz"})
+	for range matches {
+		t.Fatal("无效模式不应命中")
+	}
+	if err() == nil {
+		t.Fatal("无效模式的Stream应返回错误")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	matches, err = NewGenerator(WithWorkers(1)).Stream(ctx, Pattern{Prefix: strings.Repeat("f", 40)})
+	for range matches {
+	}
+	if !errors.Is(err(), context.Canceled) {
+		t.Fatalf("取消后Stream返回 %v，期望 context.Canceled", err())
+	}
+}
+
+func TestSeenSkipsDuplicates(t *testing.T) {
+	// 前3个命中视为重复，不计入Count
+	var calls int
+	opts := Options{Workers: 2, Count: 2, Seen: func(address string) bool {
+		calls++
+		if !strings.HasPrefix(address, "0xa") {
+			t.Errorf("Seen收到不匹配的地址 %s", address)
+		}
+		return calls <= 3
+	}}
+	g := NewGenerator(opts)
+	var found int
+	if err := g.Run(context.Background(), Pattern{Prefix: "a"}, func(Match) { found++ }); err != nil {
+		t.Fatal(err)
+	}
+	if found != 2 || g.Matches() != 2 || calls < 5 {
+		t.Fatalf("找到 %d 个，Matches() = %d，Seen调用 %d 次", found, g.Matches(), calls)
+	}
+}
+
+// waitGoroutines 等待goroutine数回落到不超过n，超时返回false
+func waitGoroutines(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+func TestRunCancel(t *testing.T) {
+	base := runtime.NumGoroutine()
+	g := NewGenerator(Options{Workers: 4, OnProgress: func(int64, time.Duration) {}})
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- g.Run(ctx, Pattern{Prefix: strings.Repeat("f", 40)}, func(Match) {}) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("取消后Run返回 %v，期望 context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("取消后Run未在2秒内返回")
+	}
+	if g.Count() == 0 {
+		t.Error("取消前没有生成任何候选")
+	}
+	if !waitGoroutines(base, time.Second) {
+		t.Fatalf("Run返回后仍有 %d 个goroutine，开始前为 %d", runtime.NumGoroutine(), base)
+	}
+}
+
+func TestRunCountReached(t *testing.T) {
+	base := runtime.NumGoroutine()
+	g := NewGenerator(Options{Workers: 4, Count: 3, OnProgress: func(int64, time.Duration) {}})
+	var found int
+	if err := g.Run(context.Background(), Pattern{Prefix: "a"}, func(Match) { found++ }); err != nil {
+		t.Fatal(err)
+	}
+	if found != 3 {
+		t.Fatalf("找到 %d 个，期望 3", found)
+	}
+	if !waitGoroutines(base, time.Second) {
+		t.Fatalf("Run返回后仍有 %d 个goroutine，开始前为 %d", runtime.NumGoroutine(), base)
+	}
+}
+
+// failingReader 先返回partial个字节，之后每次读取都失败
+type failingReader struct {
+	partial int
+	err     error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.partial > 0 {
+		n := min(r.partial, len(p))
+		r.partial -= n
+		for i := range p[:n] {
+			p[i] = 0xaa
+		}
+		return n, nil
+	}
+	return 0, r.err
+}
+
+func TestRunFailingRandSource(t *testing.T) {
+	failure := errors.New("随机源不可用")
+	for _, partial := range []int{0, 16, 40} {
+		var retries int
+		g := NewGenerator(Options{
+			Workers:       1,
+			RandSource:    func(int) io.Reader { return &failingReader{partial: partial, err: failure} },
+			OnSourceError: func(error) { retries++ },
+		})
+		err := g.Run(context.Background(), Pattern{Prefix: "?"}, func(m Match) {
+			t.Errorf("随机源失败时不应命中: %s", m.Address)
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("partial=%d: Run返回 %v，期望包含 %v", partial, err, failure)
+		}
+		if retries != maxSourceRetries {
+			t.Errorf("partial=%d: 重试 %d 次，期望 %d", partial, retries, maxSourceRetries)
+		}
+		if g.Matches() != 0 {
+			t.Errorf("partial=%d: Matches() = %d", partial, g.Matches())
+		}
+	}
+}
+
+func TestPrivateKeyHexSmallScalar(t *testing.T) {
+	// D=1只有一个非零字节，编码时须左补零到64位
+	next := eoaSource(func() (*ecdsa.PrivateKey, error) { return privateKeyFromScalar(big.NewInt(1)), nil })
+	m, err := next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewGenerator().complete(&m, Pattern{})
+	if want := strings.Repeat("0", 63) + "1"; m.PrivateKey != want {
+		t.Fatalf("私钥为 %q，期望 %q", m.PrivateKey, want)
+	}
+	priv, err := crypto.HexToECDSA(m.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.ToLower(crypto.PubkeyToAddress(priv.PublicKey).Hex()); got != m.Address {
+		t.Fatalf("私钥还原的地址为 %s，期望 %s", got, m.Address)
+	}
+}
+
+// TestRunConcurrentCounters 搜索期间并发读取计数，配合 go test -race 检查计数器的数据竞争
+func TestRunConcurrentCounters(t *testing.T) {
+	var g *Generator
+	g = NewGenerator(Options{Workers: 4, Count: 20, ProgressInterval: time.Millisecond,
+		OnProgress: func(int64, time.Duration) { _ = g.Matches() }})
+	ctx, cancel := context.WithCancel(context.Background())
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for ctx.Err() == nil {
+			_, _, _ = g.Count(), g.WorkerCounts(), g.Elapsed()
+		}
+	}()
+	err := g.Run(ctx, Pattern{Prefix: "a"}, func(m Match) {
+		if m.Attempts <= 0 || m.Attempts > g.Count() {
+			t.Errorf("Attempts = %d，Count() = %d", m.Attempts, g.Count())
+		}
+	})
+	cancel()
+	<-polled
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, n := range g.WorkerCounts() {
+		total += n
+	}
+	if total != g.Count() || g.Matches() != 20 {
+		t.Fatalf("各worker合计 %d，Count() = %d，Matches() = %d", total, g.Count(), g.Matches())
+	}
+}
+
+func TestRejectedKeyZeroed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := NewGenerator(WithWorkers(1))
+	var checked int
+	g.rejected = func(key []byte) {
+		if len(key) != 32 {
+			t.Errorf("私钥缓冲长度为 %d", len(key))
+		}
+		for _, b := range key {
+			if b != 0 {
+				t.Errorf("未命中的候选私钥未清零: %x", key)
+				break
+			}
+		}
+		if checked++; checked == 100 {
+			cancel()
+		}
+	}
+	g.Run(ctx, Pattern{Prefix: strings.Repeat("f", 40)}, func(Match) {})
+	if checked < 100 {
+		t.Fatalf("只检查了 %d 个候选", checked)
+	}
+}
+
+// BenchmarkMatchLoop worker热循环的一次迭代：生成候选、检查模式、清零私钥，哈希器在候选之间复用
+func BenchmarkMatchLoop(b *testing.B) {
+	next := eoaSource(NewFastKeygen(rand.Reader))
+	matcher := newRawMatcher(Pattern{Prefix: strings.Repeat("f", 40)}.normalize())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m, err := next()
+		if err != nil {
+			b.Fatal(err)
+		}
+		matcher.match(&m)
+		clear(m.key)
+	}
+}
+
+// BenchmarkMatchLoopNewHasher 同BenchmarkMatchLoop，但每个候选新建哈希器并编码地址字符串，即复用之前的做法
+func BenchmarkMatchLoopNewHasher(b *testing.B) {
+	keygen := NewFastKeygen(rand.Reader)
+	p := Pattern{Prefix: strings.Repeat("f", 40)}.normalize()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		priv, err := keygen()
+		if err != nil {
+			b.Fatal(err)
+		}
+		p.match(PrivateKeyToAddress(priv))
+		wipeScalar(priv.D)
+	}
+}
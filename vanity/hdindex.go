@@ -0,0 +1,76 @@
+package vanity
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// HDIndex 在已有助记词的派生空间中搜索：依次尝试 Parent/i 的非强化索引i，命中时输出索引对应的完整路径
+// 父扩展私钥只在NewHDIndex中派生一次，之后每个候选只需一次HMAC-SHA512和一次标量乘法
+type HDIndex struct {
+	Parent []uint32 // 父路径，如 m/44'/60'/0'/0
+
+	key   *big.Int // 父私钥
+	chain []byte   // 父链码
+	pub   []byte   // 父压缩公钥
+}
+
+// NewHDIndex 由助记词（无BIP-39密码）派生parent对应的父扩展私钥
+func NewHDIndex(mnemonic string, parent []uint32) (*HDIndex, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	key, chain, err := deriveExtended(seed, parent)
+	if err != nil {
+		return nil, err
+	}
+	x, y := secp256k1.S256().ScalarBaseMult(padScalar(key))
+	return &HDIndex{Parent: parent, key: key, chain: chain, pub: compressPubkey(x, y)}, nil
+}
+
+// FormatPath 将派生路径格式化为 m/44'/60'/0'/0/0 的形式
+func FormatPath(path []uint32) string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range path {
+		b.WriteString("/")
+		if index >= hardened {
+			b.WriteString(strconv.FormatUint(uint64(index-hardened), 10) + "'")
+		} else {
+			b.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return b.String()
+}
+
+// source 返回从索引start开始、每次前进step的候选生成函数，非强化索引用尽后返回错误
+func (h *HDIndex) source(start, step uint64) candidateSource {
+	next := start
+	hasher := newKeccakHasher()
+	var privBytes [32]byte
+	return func() (Match, error) {
+		for {
+			if next >= hardened {
+				return Match{}, errors.New("非强化派生索引已用尽")
+			}
+			index := uint32(next)
+			next += step
+			child, _, err := deriveChild(h.key, h.chain, h.pub, index)
+			if err != nil {
+				continue
+			}
+			priv := privateKeyFromScalar(child)
+			child.FillBytes(privBytes[:])
+			address := hasher.addressBytes(priv)
+			wipeScalar(child)
+			return Match{raw: address, key: privBytes[:], hd: h, index: index}, nil
+		}
+	}
+}
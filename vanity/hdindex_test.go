@@ -0,0 +1,40 @@
+package vanity
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHDIndexPath(t *testing.T) {
+	const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	parent, _ := ParsePath("m/44'/60'/0'/0")
+	h, err := NewHDIndex(mnemonic, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 通配符前缀匹配每个索引，单个worker按0、1、2的顺序尝试
+	g := NewGenerator(Options{Workers: 1, Count: 3, HDIndex: h})
+	var paths []string
+	if err := g.Run(context.Background(), Pattern{Prefix: "?"}, func(m Match) {
+		paths = append(paths, m.Path)
+		full, err := ParsePath(m.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := MnemonicToKey(mnemonic, full)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if PrivateKeyToAddress(key) != m.Address {
+			t.Errorf("%s 派生的地址与 %s 不符", m.Path, m.Address)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"m/44'/60'/0'/0/0", "m/44'/60'/0'/0/1", "m/44'/60'/0'/0/2"}
+	for i := range want {
+		if i >= len(paths) || paths[i] != want[i] {
+			t.Fatalf("路径为 %q，期望 %q", paths, want)
+		}
+	}
+}
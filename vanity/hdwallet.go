@@ -0,0 +1,175 @@
+package vanity
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultPath 以太坊第一个账户的BIP-44派生路径
+const DefaultPath = "m/44'/60'/0'/0/0"
+
+// hardened BIP-32强化派生的索引偏移
+const hardened = 0x80000000
+
+// Mnemonic BIP-39助记词模式参数，候选私钥由随机助记词按Path派生
+type Mnemonic struct {
+	Words int      // 助记词个数，12或24
+	Path  []uint32 // BIP-32派生路径，nil时为DefaultPath
+}
+
+// ParsePath 解析形如 m/44'/60'/0'/0/0 的BIP-32派生路径
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, errors.New("派生路径必须以m开头")
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		var offset uint32
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			offset = hardened
+			part = part[:len(part)-1]
+		}
+		n, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("无效的路径索引 %q", part)
+		}
+		indices = append(indices, uint32(n)+offset)
+	}
+	return indices, nil
+}
+
+// DeriveKey 按BIP-32从种子派生path对应的私钥
+func DeriveKey(seed []byte, path []uint32) (*ecdsa.PrivateKey, error) {
+	key, _, err := deriveExtended(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	return privateKeyFromScalar(key), nil
+}
+
+// deriveExtended 按BIP-32从种子派生path对应的扩展私钥，返回私钥和链码
+func deriveExtended(seed []byte, path []uint32) (*big.Int, []byte, error) {
+	curve := secp256k1.S256()
+
+	I := hmacSHA512([]byte("Bitcoin seed"), seed)
+	key, chain := new(big.Int).SetBytes(I[:32]), I[32:]
+	if !validScalar(key) {
+		return nil, nil, errors.New("无效的主私钥")
+	}
+
+	for _, index := range path {
+		var pub []byte
+		if index < hardened {
+			x, y := curve.ScalarBaseMult(padScalar(key))
+			pub = compressPubkey(x, y)
+		}
+		child, childChain, err := deriveChild(key, chain, pub, index)
+		wipeScalar(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, chain = child, childChain
+	}
+	return key, chain, nil
+}
+
+// deriveChild 由父私钥key和链码chain派生索引为index的子私钥及子链码，非强化派生时pub为父压缩公钥
+// 子私钥无效（概率约2^-127）时返回错误，按BIP-32应跳过该索引
+func deriveChild(key *big.Int, chain, pub []byte, index uint32) (*big.Int, []byte, error) {
+	var data []byte
+	if index >= hardened {
+		data = make([]byte, 33, 37)
+		key.FillBytes(data[1:33])
+	} else {
+		data = append(make([]byte, 0, 37), pub...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	I := hmacSHA512(chain, data)
+	clear(data)
+	il := new(big.Int).SetBytes(I[:32])
+	if il.Cmp(curveOrder) >= 0 {
+		return nil, nil, errors.New("派生出无效的子私钥")
+	}
+	child := il.Add(il, key).Mod(il, curveOrder)
+	if child.Sign() == 0 {
+		return nil, nil, errors.New("派生出无效的子私钥")
+	}
+	return child, I[32:], nil
+}
+
+// MnemonicToKey 由助记词（无BIP-39密码）按path派生私钥
+func MnemonicToKey(mnemonic string, path []uint32) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	return DeriveKey(seed, path)
+}
+
+// source 返回从r读取熵生成随机助记词并派生私钥及地址的候选生成函数，派生失败时换一个助记词重试
+func (c *Mnemonic) source(r io.Reader) candidateSource {
+	path := c.Path
+	if path == nil {
+		path, _ = ParsePath(DefaultPath)
+	}
+	// 12个词对应128位熵，24个词对应256位熵
+	entropy := make([]byte, c.Words/3*4)
+	var privBytes [32]byte
+	h := newKeccakHasher()
+	return func() (Match, error) {
+		for {
+			if _, err := io.ReadFull(r, entropy); err != nil {
+				return Match{}, err
+			}
+			mnemonic, err := bip39.NewMnemonic(entropy)
+			clear(entropy)
+			if err != nil {
+				return Match{}, err
+			}
+			priv, err := MnemonicToKey(mnemonic, path)
+			if err != nil {
+				continue
+			}
+			priv.D.FillBytes(privBytes[:])
+			address := h.addressBytes(priv)
+			wipeScalar(priv.D)
+			return Match{raw: address, key: privBytes[:], Mnemonic: mnemonic}, nil
+		}
+	}
+}
+
+// hmacSHA512 计算HMAC-SHA512
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// padScalar 将标量左补零到32字节
+func padScalar(k *big.Int) []byte {
+	var b [32]byte
+	k.FillBytes(b[:])
+	return b[:]
+}
+
+// compressPubkey 返回33字节压缩公钥，首字节0x02/0x03表示Y的奇偶
+func compressPubkey(x, y *big.Int) []byte {
+	b := make([]byte, 33, 37)
+	b[0] = 0x02 + byte(y.Bit(0))
+	x.FillBytes(b[1:])
+	return b
+}
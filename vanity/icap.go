@@ -0,0 +1,55 @@
+package vanity
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// icapDirectLen Direct ICAP的BBAN长度，20字节地址小于36^30（约前5位为0）时可用
+const icapDirectLen = 30
+
+// icapBasicLen Basic ICAP的BBAN长度，可表示任意地址，但总长35位不符合IBAN标准
+const icapBasicLen = 31
+
+// ICAPAddress 将带0x前缀的以太坊地址转换为ICAP（XE + 2位mod-97校验 + base36地址）
+// 能用30位表示时为Direct格式，否则为31位的Basic格式；输入不是40位十六进制时返回空字符串
+func ICAPAddress(address string) string {
+	body := strings.TrimPrefix(address, "0x")
+	if len(body) != 40 {
+		return ""
+	}
+	raw, err := hex.DecodeString(body)
+	if err != nil {
+		return ""
+	}
+	bban := strings.ToUpper(new(big.Int).SetBytes(raw).Text(36))
+	width := icapDirectLen
+	if len(bban) > icapDirectLen {
+		width = icapBasicLen
+	}
+	bban = strings.Repeat("0", width-len(bban)) + bban
+	return "XE" + icapChecksum(bban) + bban
+}
+
+// icapChecksum 按IBAN规则计算校验位：BBAN ++ "XE00" 中字母换成10~35，98减去对97取余
+func icapChecksum(bban string) string {
+	mod := 0
+	for _, c := range bban + "XE00" {
+		v := int(c - '0')
+		if c >= 'A' {
+			v = int(c-'A') + 10
+		}
+		if v >= 10 {
+			mod = (mod*100 + v) % 97
+		} else {
+			mod = (mod*10 + v) % 97
+		}
+	}
+	check := strconv.Itoa(98 - mod)
+	if len(check) == 1 {
+		check = "0" + check
+	}
+	return check
+}
@@ -0,0 +1,105 @@
+package vanity
+
+import "strings"
+
+// rawMatcher 按20字节地址直接检查模式，避免每个候选都编码成十六进制字符串
+// 只有前导零、前缀、后缀（可含通配符）、末尾整数和目标地址汉明距离且不区分大小写的模式走快速路径，其余编码后交给Pattern.match
+type rawMatcher struct {
+	p      Pattern
+	empty  bool // 空模式，不匹配任何地址
+	fast   bool
+	zeros  int        // 至少多少个前导零半字节
+	prefix []byte     // 前缀各位的半字节值，通配符为nibbleAny
+	offset int        // 前缀开始的半字节位置
+	suffix []byte     // 后缀各位的半字节值
+	sint   *SuffixInt // 末尾整数的条件，nil为不限
+	target []byte     // 目标地址各位的半字节值，nil为不限
+	dist   int        // 与目标地址的最大汉明距离
+}
+
+// nibbleAny 表示通配符的半字节值
+const nibbleAny = 0xff
+
+// newRawMatcher 为规范化后的模式创建匹配器
+func newRawMatcher(p Pattern) *rawMatcher {
+	m := &rawMatcher{p: p, empty: p.empty()}
+	m.fast = !p.caseSensitive() && p.encoding() == nil && p.Regex == nil && p.Contains == "" && p.MinUpper == 0 &&
+		p.Palindrome == 0 && p.Charset == "" && p.Repeat == 0 && p.Any == nil
+	if !m.fast {
+		return m
+	}
+	m.zeros = max(p.Zeros, 2*p.ZeroBytes)
+	m.offset = 2 * p.ZeroBytes
+	m.prefix = nibbles(p.Prefix)
+	m.suffix = nibbles(p.Suffix)
+	m.sint = p.SuffixInt
+	if p.Target != "" {
+		m.target = nibbles(strings.ToLower(p.Target))
+		m.dist = p.Distance
+	}
+	return m
+}
+
+// nibbles 将小写十六进制模式转换为半字节值
+func nibbles(s string) []byte {
+	out := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == wildcard:
+			out[i] = nibbleAny
+		case c <= '9':
+			out[i] = c - '0'
+		default:
+			out[i] = c - 'a' + 10
+		}
+	}
+	return out
+}
+
+// nibbleAt 返回地址第i个半字节
+func nibbleAt(a *[20]byte, i int) byte {
+	if i%2 == 0 {
+		return a[i/2] >> 4
+	}
+	return a[i/2] & 0x0f
+}
+
+// match 检查候选是否匹配
+func (m *rawMatcher) match(c *Match) bool {
+	if m.empty {
+		return false
+	}
+	if !m.fast {
+		return m.p.match(hexAddress(c.raw))
+	}
+	for i := 0; i < m.zeros; i++ {
+		if nibbleAt(&c.raw, i) != 0 {
+			return false
+		}
+	}
+	for i, n := range m.prefix {
+		if n != nibbleAny && nibbleAt(&c.raw, m.offset+i) != n {
+			return false
+		}
+	}
+	start := addressLen - len(m.suffix)
+	for i, n := range m.suffix {
+		if n != nibbleAny && nibbleAt(&c.raw, start+i) != n {
+			return false
+		}
+	}
+	if m.sint != nil && !m.sint.match(suffixValue(&c.raw, m.sint.Digits)) {
+		return false
+	}
+	if m.target != nil {
+		diff := 0
+		for i, n := range m.target {
+			if nibbleAt(&c.raw, i) != n {
+				if diff++; diff > m.dist {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
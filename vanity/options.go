@@ -0,0 +1,42 @@
+package vanity
+
+import "io"
+
+// Option 生成器配置项，传给NewGenerator后按顺序应用
+// Options本身也是一个Option，整体替换此前的配置
+type Option interface {
+	apply(*Options)
+}
+
+// apply 以o整体替换配置
+func (o Options) apply(dst *Options) { *dst = o }
+
+// optionFunc 以函数实现的配置项
+type optionFunc func(*Options)
+
+func (f optionFunc) apply(o *Options) { f(o) }
+
+// WithWorkers 设置worker数量，<=0时使用CPU核心数（默认）
+func WithWorkers(n int) Option {
+	return optionFunc(func(o *Options) { o.Workers = n })
+}
+
+// WithCount 设置需要找到的匹配数量，<=0时为1（默认）
+func WithCount(n int64) Option {
+	return optionFunc(func(o *Options) { o.Count = n })
+}
+
+// WithPattern 设置Search搜索的模式
+func WithPattern(p Pattern) Option {
+	return optionFunc(func(o *Options) { o.Pattern = p })
+}
+
+// WithOutput 每个匹配另向w写一行"校验和地址 私钥"，默认为nil即不写
+func WithOutput(w io.Writer) Option {
+	return optionFunc(func(o *Options) { o.Output = w })
+}
+
+// WithChecksumMatch 为true时Search按EIP-55校验和区分大小写匹配，与WithPattern的先后顺序无关
+func WithChecksumMatch(on bool) Option {
+	return optionFunc(func(o *Options) { o.checksum = on })
+}
@@ -0,0 +1,531 @@
+package vanity
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// addressLen 匹配目标的长度，即不含0x的40位十六进制
+const addressLen = 40
+
+// Pattern 描述要匹配的地址模式，指定的多个条件须同时满足
+type Pattern struct {
+	Prefix     string         // 前缀模式，匹配不含0x的40位十六进制，?匹配任意一个字符，指定ZeroBytes时紧跟在零字节之后
+	Suffix     string         // 后缀模式，?匹配任意一个字符
+	SuffixInt  *SuffixInt     // 末尾若干个十六进制字符作为整数须满足的条件，nil为不限
+	Contains   string         // 地址任意位置包含的十六进制子串
+	Target     string         // 目标地址（40位十六进制，不含0x），与其相差不超过Distance个半字节即匹配，不区分大小写
+	Distance   int            // 与Target的最大汉明距离（不同的半字节数）
+	MinUpper   int            // EIP-55校验和地址中至少多少个大写字母
+	Regex      *regexp.Regexp // 正则模式，匹配不含0x的40位十六进制
+	Zeros      int            // 至少多少个前导零半字节
+	ZeroBytes  int            // 至少多少个前导零字节
+	Palindrome int            // 首尾各多少个字符互为镜像，20即整个地址为回文
+	Charset    string         // 允许的字符集，如"0123456789"
+	CharsetLen int            // 字符集约束的字符数，0为整个地址
+	CharsetEnd bool           // 字符集约束末尾CharsetLen个字符，否则为开头
+	Repeat     int            // 开头至少连续多少个相同字符
+	RepeatEnd  bool           // Repeat约束末尾而不是开头
+	Any        *PatternSet    // 多个前缀/后缀中命中任意一个
+	Checksum   bool           // 按EIP-55校验和地址区分大小写匹配
+	IgnoreCase bool           // 忽略大小写，模式和地址都按小写比较，优先于Checksum
+	Tron       bool           // 按Tron base58check地址（以T开头）匹配，只支持前缀、后缀和正则
+	ICAP       bool           // 按ICAP地址（以XE开头）匹配，只支持前缀、后缀和正则
+}
+
+// hexDigits 模式中允许的字符
+const hexDigits = "0123456789abcdefABCDEF"
+
+// wildcard 前缀、后缀中匹配任意一个字符的通配符
+const wildcard = '?'
+
+// checkHex 检查s只含十六进制字符，name为出错时的字段名
+func checkHex(name, s string) error {
+	for _, c := range s {
+		if !strings.ContainsRune(hexDigits, c) {
+			return fmt.Errorf("%s包含非十六进制字符 %q", name, c)
+		}
+	}
+	return nil
+}
+
+// checkAffix 检查前缀或后缀只含十六进制字符和通配符
+func checkAffix(name, s string) error {
+	return checkHex(name, strings.ReplaceAll(s, string(wildcard), ""))
+}
+
+// matchWildcard 判断等长的s与pat是否逐字符相同，pat中的通配符匹配任意字符
+func matchWildcard(s, pat string) bool {
+	for i := 0; i < len(pat); i++ {
+		if pat[i] != s[i] && pat[i] != wildcard {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrEmptyPattern 模式未指定任何条件
+var ErrEmptyPattern = errors.New("未指定任何匹配条件")
+
+// Validate 检查模式是否可能匹配
+func (p Pattern) Validate() error {
+	if p.empty() {
+		return ErrEmptyPattern
+	}
+	if e := p.encoding(); e != nil {
+		return p.validateEncoded(e)
+	}
+	if 2*p.ZeroBytes+len(p.Prefix)+len(p.Suffix) > addressLen {
+		return errors.New("前导零字节、前缀与后缀总长度超过地址长度")
+	}
+	if p.Zeros < 0 || p.Zeros > addressLen || p.ZeroBytes < 0 || p.ZeroBytes > addressLen/2 {
+		return errors.New("前导零个数超出范围")
+	}
+	if p.Palindrome < 0 || p.Palindrome > addressLen/2 {
+		return errors.New("回文长度超出范围")
+	}
+	if err := checkAffix("前缀", p.Prefix); err != nil {
+		return err
+	}
+	if err := checkAffix("后缀", p.Suffix); err != nil {
+		return err
+	}
+	if err := checkHex("子串", p.Contains); err != nil {
+		return err
+	}
+	if len(p.Contains) > addressLen {
+		return errors.New("子串长度超过地址长度")
+	}
+	if p.Target != "" {
+		if len(p.Target) != addressLen {
+			return fmt.Errorf("目标地址应为40位十六进制，实际%d位", len(p.Target))
+		}
+		if err := checkHex("目标地址", p.Target); err != nil {
+			return err
+		}
+	}
+	if p.Distance < 0 || p.Distance > addressLen {
+		return errors.New("汉明距离超出范围")
+	}
+	if p.Distance > 0 && p.Target == "" {
+		return errors.New("汉明距离需要同时指定目标地址")
+	}
+	if p.MinUpper < 0 || p.MinUpper > addressLen {
+		return errors.New("大写字母个数超出范围")
+	}
+	if err := checkHex("字符集", p.Charset); err != nil {
+		return err
+	}
+	if p.CharsetLen < 0 || p.CharsetLen > addressLen {
+		return errors.New("字符集约束长度超出范围")
+	}
+	if p.Repeat < 0 || p.Repeat > addressLen {
+		return errors.New("重复长度超出范围")
+	}
+	if err := p.checkOverlap(); err != nil {
+		return err
+	}
+	if p.SuffixInt != nil {
+		return p.SuffixInt.validate()
+	}
+	return nil
+}
+
+// checkOverlap 检查前后缀与前导零、重复约束是否矛盾：它们约束地址开头（或末尾）的同一段字符
+func (p Pattern) checkOverlap() error {
+	prefix, suffix := p.Prefix, p.Suffix
+	if !p.caseSensitive() {
+		prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	}
+	offset := 2 * p.ZeroBytes
+	for i := 0; i < len(prefix) && offset+i < p.Zeros; i++ {
+		if prefix[i] != '0' && prefix[i] != wildcard {
+			return fmt.Errorf("前缀第%d个字符%q与%d个前导零矛盾", i+1, prefix[i], p.Zeros)
+		}
+	}
+	if p.Repeat < 2 {
+		return nil
+	}
+	// 重复段内的固定字符须全部相同；前导零固定了开头字符为0
+	var run byte
+	fixed := func(c byte) bool {
+		if c == wildcard {
+			return true
+		}
+		if run == 0 {
+			run = c
+		}
+		return c == run
+	}
+	if p.RepeatEnd {
+		for i := 0; i < len(suffix) && i < p.Repeat; i++ {
+			if !fixed(suffix[len(suffix)-1-i]) {
+				return fmt.Errorf("后缀%q与末尾%d个重复字符矛盾", p.Suffix, p.Repeat)
+			}
+		}
+		return nil
+	}
+	if offset > 0 || p.Zeros > 0 {
+		run = '0'
+	}
+	for i := 0; i < len(prefix) && offset+i < p.Repeat; i++ {
+		if !fixed(prefix[i]) {
+			return fmt.Errorf("前缀%q与开头%d个重复字符矛盾", p.Prefix, p.Repeat)
+		}
+	}
+	return nil
+}
+
+// Difficulty 返回期望尝试次数，无法估算（如正则）时返回0，条件互相矛盾时返回+Inf
+func (p Pattern) Difficulty() float64 {
+	if p.empty() || p.Regex != nil {
+		return 0
+	}
+	if e := p.encoding(); e != nil {
+		return p.encodedDifficulty(e)
+	}
+	if p.checkOverlap() != nil {
+		return math.Inf(1)
+	}
+	// 前缀紧跟在前导零字节之后，两者相乘；前导零半字节约束同一段开头，取其中更难的一个
+	lead := math.Max(math.Pow(16, float64(p.Zeros)), math.Pow(256, float64(p.ZeroBytes))*p.textDifficulty(p.Prefix))
+	d := lead * p.textDifficulty(p.Suffix)
+	// 末尾整数与字符串后缀约束同一段末尾，取其中更难的一个
+	if p.SuffixInt != nil {
+		d = math.Max(d, lead*p.SuffixInt.difficulty())
+	}
+	// 每对镜像字符固定其中一个，与前后缀重叠时按更难的估算
+	d = math.Max(d, math.Pow(16, float64(p.Palindrome)))
+	if p.Contains != "" {
+		d = math.Max(d, p.containsDifficulty())
+	}
+	if p.Target != "" {
+		d = math.Max(d, distanceDifficulty(p.Distance))
+	}
+	// 每位是字母的概率为6/16，校验和使其大写的概率为1/2
+	if p.MinUpper > 0 {
+		d = math.Max(d, 1/binomialRange(addressLen, p.MinUpper, addressLen, 3.0/16))
+	}
+	if p.Charset != "" {
+		d = math.Max(d, math.Pow(16/float64(len(p.charset())), float64(p.charsetSpan())))
+	}
+	// 重复的字符可以是16个中任意一个，第一个字符不受约束
+	if p.Repeat > 1 {
+		d = math.Max(d, math.Pow(16, float64(p.Repeat-1)))
+	}
+	if p.Any != nil {
+		d = math.Max(d, p.Any.difficulty(p.textDifficulty))
+	}
+	return d
+}
+
+// String 返回模式的简短描述，如"prefix=dead checksum"，键名与命令行参数一致
+func (p Pattern) String() string {
+	var parts []string
+	add := func(format string, args ...any) { parts = append(parts, fmt.Sprintf(format, args...)) }
+	if p.Prefix != "" {
+		add("prefix=%s", p.Prefix)
+	}
+	if p.Suffix != "" {
+		add("suffix=%s", p.Suffix)
+	}
+	if p.SuffixInt != nil {
+		add("%s", p.SuffixInt)
+	}
+	if p.Contains != "" {
+		add("contains=%s", p.Contains)
+	}
+	if p.Target != "" {
+		add("target=%s", p.Target)
+		add("distance=%d", p.Distance)
+	}
+	if p.MinUpper > 0 {
+		add("min-upper=%d", p.MinUpper)
+	}
+	if p.Regex != nil {
+		add("regex=%s", p.Regex)
+	}
+	if p.Zeros > 0 {
+		add("zeros=%d", p.Zeros)
+	}
+	if p.ZeroBytes > 0 {
+		add("zero-bytes=%d", p.ZeroBytes)
+	}
+	if p.Palindrome > 0 {
+		add("palindrome=%d", p.Palindrome)
+	}
+	if p.Charset != "" {
+		add("charset=%s", p.Charset)
+		if p.CharsetLen > 0 {
+			add("charset-len=%d", p.CharsetLen)
+		}
+		if p.CharsetEnd {
+			add("charset-end")
+		}
+	}
+	if p.Repeat > 0 {
+		add("repeat=%d", p.Repeat)
+		if p.RepeatEnd {
+			add("repeat-end")
+		}
+	}
+	if p.Any != nil {
+		add("patterns=%d", p.Any.Len())
+	}
+	if p.Checksum {
+		add("checksum")
+	}
+	if p.IgnoreCase {
+		add("ignore-case")
+	}
+	if p.Tron {
+		add("tron")
+	}
+	if p.ICAP {
+		add("icap")
+	}
+	return strings.Join(parts, " ")
+}
+
+// textDifficulty 返回固定字符串的期望尝试次数，通配符位不计，校验和模式下每个字母位再乘2
+func (p Pattern) textDifficulty(s string) float64 {
+	d := 1.0
+	for _, c := range s {
+		if c == wildcard {
+			continue
+		}
+		d *= 16
+		if p.caseSensitive() && c > '9' {
+			d *= 2
+		}
+	}
+	return d
+}
+
+// containsDifficulty 返回子串出现在任意位置的期望尝试次数
+// 子串可在40-m+1个位置出现，按各位置独立近似：P = 1-(1-1/16^m)^(40-m+1)，比同长度前缀容易约40-m+1倍
+func (p Pattern) containsDifficulty() float64 {
+	positions := float64(addressLen - len(p.Contains) + 1)
+	return -1 / math.Expm1(positions*math.Log1p(-1/p.textDifficulty(p.Contains)))
+}
+
+// distanceDifficulty 返回与目标汉明距离不超过k的期望尝试次数，40个半字节各以15/16的概率不同
+func distanceDifficulty(k int) float64 {
+	return 1 / binomialRange(addressLen, 0, k, 15.0/16)
+}
+
+// binomialRange 返回n次成功率为p的独立试验中成功次数在[lo, hi]内的概率：Σ C(n,i)·p^i·(1-p)^(n-i)
+func binomialRange(n, lo, hi int, p float64) float64 {
+	var prob float64
+	for i := lo; i <= hi; i++ {
+		c, _ := new(big.Float).SetInt(new(big.Int).Binomial(int64(n), int64(i))).Float64()
+		prob += c * math.Pow(p, float64(i)) * math.Pow(1-p, float64(n-i))
+	}
+	return prob
+}
+
+// empty 判断模式是否未指定任何条件
+func (p Pattern) empty() bool {
+	return p.Prefix == "" && p.Suffix == "" && p.SuffixInt == nil && p.Contains == "" && p.Target == "" && p.MinUpper == 0 && p.Regex == nil && p.Zeros == 0 && p.ZeroBytes == 0 &&
+		p.Palindrome == 0 && p.Charset == "" && p.Repeat == 0 &&
+		p.Any == nil
+}
+
+// caseSensitive 判断是否区分大小写比较：EIP-55校验和形式，或按原样比较的Tron、ICAP编码
+func (p Pattern) caseSensitive() bool { return (p.Checksum || p.encoding() != nil) && !p.IgnoreCase }
+
+// normalize 返回用于搜索的模式：不区分大小写时前缀、后缀和模式列表统一为小写，IgnoreCase时正则也忽略大小写
+// 只在搜索开始时调用一次，避免在热循环中转换
+func (p Pattern) normalize() Pattern {
+	if p.caseSensitive() {
+		return p
+	}
+	p.Prefix = strings.ToLower(p.Prefix)
+	p.Suffix = strings.ToLower(p.Suffix)
+	p.Contains = strings.ToLower(p.Contains)
+	if p.Any != nil {
+		p.Any = p.Any.lower()
+	}
+	if p.IgnoreCase && p.Regex != nil {
+		p.Regex = regexp.MustCompile("(?i)" + p.Regex.String())
+	}
+	return p
+}
+
+// target 返回用于比较的40位十六进制，校验和模式下为EIP-55大小写形式，均去掉0x前缀
+func (p Pattern) target(address string) string {
+	if p.caseSensitive() {
+		return ToChecksumAddress(address)[2:]
+	}
+	return address[2:]
+}
+
+// Match 检查地址（带0x前缀的小写形式）是否匹配模式，空模式、无效模式或长度不对的地址都不匹配
+// 每次调用都会检查并规范化模式，大量地址请用Generator
+func (p Pattern) Match(address string) bool {
+	if len(address) != 2+addressLen || p.Validate() != nil {
+		return false
+	}
+	return p.normalize().match(address)
+}
+
+// match 检查地址是否匹配模式，空模式不匹配任何地址
+func (p Pattern) match(address string) bool {
+	if p.empty() {
+		return false
+	}
+	if e := p.encoding(); e != nil {
+		return p.matchEncoded(e.encode(address))
+	}
+	if n := LeadingZeros(address); n < p.Zeros || n < 2*p.ZeroBytes {
+		return false
+	}
+	target := p.target(address)
+	off := 2 * p.ZeroBytes
+	if !matchWildcard(target[off:off+len(p.Prefix)], p.Prefix) || !matchWildcard(target[len(target)-len(p.Suffix):], p.Suffix) {
+		return false
+	}
+	if p.SuffixInt != nil && !p.SuffixInt.matchHex(address[2:]) {
+		return false
+	}
+	if p.Contains != "" && !strings.Contains(target, p.Contains) {
+		return false
+	}
+	if p.Target != "" && HammingDistance(address, p.Target) > p.Distance {
+		return false
+	}
+	if p.MinUpper > 0 && CountUpper(ToChecksumAddress(address)) < p.MinUpper {
+		return false
+	}
+	if !isPalindrome(target, p.Palindrome) {
+		return false
+	}
+	if p.Charset != "" && !p.inCharset(target) {
+		return false
+	}
+	if p.Repeat > 0 && RunLength(target, p.RepeatEnd) < p.Repeat {
+		return false
+	}
+	if p.Any != nil {
+		if _, ok := p.Any.lookup(target); !ok {
+			return false
+		}
+	}
+	return p.Regex == nil || p.Regex.MatchString(target)
+}
+
+// charset 返回去重后的字符集，非校验和模式下字母统一为小写
+func (p Pattern) charset() string {
+	set := p.Charset
+	if !p.caseSensitive() {
+		set = strings.ToLower(set)
+	}
+	var b strings.Builder
+	for _, c := range set {
+		if !strings.ContainsRune(b.String(), c) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// charsetSpan 返回字符集约束的字符数
+func (p Pattern) charsetSpan() int {
+	if p.CharsetLen == 0 {
+		return addressLen
+	}
+	return p.CharsetLen
+}
+
+// inCharset 判断target被约束的部分是否全部由字符集中的字符组成
+func (p Pattern) inCharset(target string) bool {
+	n := p.charsetSpan()
+	part := target[:n]
+	if p.CharsetEnd {
+		part = target[len(target)-n:]
+	}
+	set := p.charset()
+	for i := 0; i < len(part); i++ {
+		if strings.IndexByte(set, part[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RunLength 返回地址（可带0x前缀）开头或fromEnd时末尾连续相同字符的个数
+func RunLength(address string, fromEnd bool) int {
+	s := strings.TrimPrefix(address, "0x")
+	if s == "" {
+		return 0
+	}
+	n := 1
+	if fromEnd {
+		for n < len(s) && s[len(s)-1-n] == s[len(s)-1] {
+			n++
+		}
+		return n
+	}
+	for n < len(s) && s[n] == s[0] {
+		n++
+	}
+	return n
+}
+
+// LabelHashPrefix 返回keccak256(label)前k个字节的十六进制（不含0x），作为前缀即匹配与类似ENS标签哈希开头相同的地址
+// k须在1到20之间
+func LabelHashPrefix(label string, k int) (string, error) {
+	if k < 1 || k > 20 {
+		return "", fmt.Errorf("字节数应在1到20之间，实际%d", k)
+	}
+	return hex.EncodeToString(Keccak256([]byte(label))[:k]), nil
+}
+
+// CountUpper 返回s中大写字母的个数，用于统计EIP-55校验和地址中的大写字母
+func CountUpper(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 'A' && s[i] <= 'Z' {
+			n++
+		}
+	}
+	return n
+}
+
+// HammingDistance 返回两个地址（可带0x前缀，不区分大小写）不同的半字节数，长度不同的部分全部计为不同
+func HammingDistance(a, b string) int {
+	a = strings.ToLower(strings.TrimPrefix(a, "0x"))
+	b = strings.ToLower(strings.TrimPrefix(b, "0x"))
+	n := max(len(a), len(b)) - min(len(a), len(b))
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// isPalindrome 判断s首尾各k个字符是否互为镜像
+func isPalindrome(s string, k int) bool {
+	for i := 0; i < k; i++ {
+		if s[i] != s[len(s)-1-i] {
+			return false
+		}
+	}
+	return true
+}
+
+// LeadingZeros 返回地址（可带0x前缀）开头连续'0'的个数
+func LeadingZeros(address string) int {
+	hexStr := strings.TrimPrefix(address, "0x")
+	n := 0
+	for n < len(hexStr) && hexStr[n] == '0' {
+		n++
+	}
+	return n
+}
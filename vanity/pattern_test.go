@@ -0,0 +1,85 @@
+package vanity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Pattern
+		ok   bool
+	}{
+		{"zeros与非零前缀", Pattern{Zeros: 2, Prefix: "ab"}, false},
+		{"zeros与部分为零的前缀", Pattern{Zeros: 2, Prefix: "0a"}, false},
+		{"zeros与零前缀", Pattern{Zeros: 2, Prefix: "00ab"}, true},
+		{"zeros与通配符前缀", Pattern{Zeros: 2, Prefix: "0?ab"}, true},
+		{"zeros短于零字节", Pattern{Zeros: 2, ZeroBytes: 1, Prefix: "ab"}, true},
+		{"zeros超出零字节", Pattern{Zeros: 4, ZeroBytes: 1, Prefix: "0a"}, false},
+		{"zeros超出零字节的零前缀", Pattern{Zeros: 4, ZeroBytes: 1, Prefix: "00a"}, true},
+		{"repeat与不同字符前缀", Pattern{Repeat: 3, Prefix: "ab"}, false},
+		{"repeat与相同字符前缀", Pattern{Repeat: 3, Prefix: "aaab"}, true},
+		{"repeat与短前缀", Pattern{Repeat: 3, Prefix: "a"}, true},
+		{"repeat与通配符前缀", Pattern{Repeat: 3, Prefix: "a?b"}, false},
+		{"repeat与通配符相同字符前缀", Pattern{Repeat: 3, Prefix: "a?a"}, true},
+		{"repeat与零字节之后的非零前缀", Pattern{Repeat: 3, ZeroBytes: 1, Prefix: "ab"}, false},
+		{"repeat覆盖不到零字节之后的前缀", Pattern{Repeat: 2, ZeroBytes: 1, Prefix: "ab"}, true},
+		{"repeat与zeros之后的非零前缀", Pattern{Repeat: 3, Zeros: 1, Prefix: "0a"}, false},
+		{"repeat与不区分大小写前缀", Pattern{Repeat: 2, Prefix: "aA"}, true},
+		{"repeat与校验和前缀", Pattern{Repeat: 2, Prefix: "aA", Checksum: true}, false},
+		{"repeat-end与不同字符后缀", Pattern{Repeat: 3, RepeatEnd: true, Suffix: "ab"}, false},
+		{"repeat-end与相同字符后缀", Pattern{Repeat: 3, RepeatEnd: true, Suffix: "cbbb"}, true},
+		{"repeat-end不约束前缀", Pattern{Repeat: 3, RepeatEnd: true, Prefix: "ab"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.Validate()
+			if (err == nil) != tt.ok {
+				t.Fatalf("Validate() = %v，期望可匹配: %v", err, tt.ok)
+			}
+			if d := tt.p.Difficulty(); math.IsInf(d, 1) == tt.ok {
+				t.Fatalf("Difficulty() = %v", d)
+			}
+		})
+	}
+}
+
+func TestDifficultyOverlap(t *testing.T) {
+	// 前缀与前导零一致时其难度包含前导零
+	p := Pattern{Zeros: 2, Prefix: "00ab"}
+	if d := p.Difficulty(); d != math.Pow(16, 4) {
+		t.Fatalf("Difficulty() = %v，期望 %v", d, math.Pow(16, 4))
+	}
+}
+
+func TestMatchHexBody(t *testing.T) {
+	// 私钥1的地址，校验和形式为 0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf
+	const address = "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"
+	tests := []struct {
+		name string
+		p    Pattern
+		want bool
+	}{
+		{"前缀", Pattern{Prefix: "7e5f"}, true},
+		{"前缀不含0x中的0", Pattern{Prefix: "0"}, false},
+		{"前缀带0x", Pattern{Prefix: "0x7e5f"}, false},
+		{"后缀", Pattern{Suffix: "5bdf"}, true},
+		{"后缀带0x", Pattern{Suffix: "0x5bdf"}, false},
+		{"前缀与后缀", Pattern{Prefix: "7e", Suffix: "df"}, true},
+		{"大小写混合的前缀", Pattern{Prefix: "7E5f"}, true},
+		{"大小写混合的后缀", Pattern{Suffix: "5BdF"}, true},
+		{"校验和前缀", Pattern{Prefix: "7E5F", Checksum: true}, true},
+		{"校验和前缀大小写不符", Pattern{Prefix: "7e5f", Checksum: true}, false},
+		{"校验和后缀", Pattern{Suffix: "5Bdf", Checksum: true}, true},
+		{"校验和后缀大小写不符", Pattern{Suffix: "5bdf", Checksum: true}, false},
+		{"忽略大小写优先于校验和", Pattern{Suffix: "5bdf", Checksum: true, IgnoreCase: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Match(address); got != tt.want {
+				t.Fatalf("Match() = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
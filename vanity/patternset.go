@@ -0,0 +1,113 @@
+package vanity
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// PatternSet 多个前缀/后缀模式，命中任意一个即匹配
+// 按长度分桶存放，每个候选只需对每种长度查一次map，不必逐个扫描
+type PatternSet struct {
+	prefixes affixSet
+	suffixes affixSet
+}
+
+// affixSet 同一方向（前缀或后缀）的模式集合
+type affixSet struct {
+	lens []int             // 出现过的模式长度，升序
+	set  map[string]string // 模式 -> 文件中的原始写法
+}
+
+// ParsePatternSet 从r读取模式列表，每行一个：
+// 普通行为前缀，以*开头为后缀（如 *beef），空行和#开头的行忽略
+func ParsePatternSet(r io.Reader) (*PatternSet, error) {
+	s := &PatternSet{
+		prefixes: affixSet{set: map[string]string{}},
+		suffixes: affixSet{set: map[string]string{}},
+	}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		target, body := &s.prefixes, strings.TrimSuffix(text, "*")
+		if strings.HasPrefix(text, "*") {
+			target, body = &s.suffixes, text[1:]
+		}
+		if body == "" || len(body) > addressLen {
+			return nil, fmt.Errorf("第%d行: 模式长度应为1到%d", line, addressLen)
+		}
+		if err := checkHex("模式", body); err != nil {
+			return nil, fmt.Errorf("第%d行: %w", line, err)
+		}
+		target.add(body, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if s.Len() == 0 {
+		return nil, fmt.Errorf("模式列表为空")
+	}
+	return s, nil
+}
+
+// Len 返回模式个数
+func (s *PatternSet) Len() int { return len(s.prefixes.set) + len(s.suffixes.set) }
+
+// lookup 返回target命中的第一个模式的原始写法
+func (s *PatternSet) lookup(target string) (string, bool) {
+	for _, n := range s.prefixes.lens {
+		if raw, ok := s.prefixes.set[target[:n]]; ok {
+			return raw, true
+		}
+	}
+	for _, n := range s.suffixes.lens {
+		if raw, ok := s.suffixes.set[target[len(target)-n:]]; ok {
+			return raw, true
+		}
+	}
+	return "", false
+}
+
+// lower 返回模式统一为小写的副本，原始写法不变
+func (s *PatternSet) lower() *PatternSet {
+	l := &PatternSet{
+		prefixes: affixSet{set: map[string]string{}},
+		suffixes: affixSet{set: map[string]string{}},
+	}
+	for _, pair := range [][2]*affixSet{{&s.prefixes, &l.prefixes}, {&s.suffixes, &l.suffixes}} {
+		for body, raw := range pair[0].set {
+			pair[1].add(strings.ToLower(body), raw)
+		}
+	}
+	return l
+}
+
+// difficulty 返回命中任意一个模式的期望尝试次数，textDifficulty为单个模式的期望次数
+func (s *PatternSet) difficulty(textDifficulty func(string) float64) float64 {
+	var prob float64
+	for _, a := range []affixSet{s.prefixes, s.suffixes} {
+		for body := range a.set {
+			prob += 1 / textDifficulty(body)
+		}
+	}
+	return 1 / prob
+}
+
+// add 加入一个模式
+func (a *affixSet) add(body, raw string) {
+	if _, ok := a.set[body]; ok {
+		return
+	}
+	a.set[body] = raw
+	i := sort.SearchInts(a.lens, len(body))
+	if i == len(a.lens) || a.lens[i] != len(body) {
+		a.lens = append(a.lens, 0)
+		copy(a.lens[i+1:], a.lens[i:])
+		a.lens[i] = len(body)
+	}
+}
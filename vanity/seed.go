@@ -0,0 +1,40 @@
+package vanity
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// seededReader 由种子确定的伪随机流，第i块为 keccak256(seed ++ stream ++ i)
+type seededReader struct {
+	in    []byte // seed ++ stream ++ 块序号
+	block uint64
+	out   [32]byte
+	off   int // out中已读的字节数
+	h     *keccakHasher
+}
+
+// NewSeededReader 返回由seed和stream确定的伪随机流，相同参数总是产生相同的字节序列
+// 不安全，仅用于测试和可复现的基准，绝不能用来生成真实使用的私钥
+func NewSeededReader(seed []byte, stream uint64) io.Reader {
+	in := binary.BigEndian.AppendUint64(append([]byte{}, seed...), stream)
+	in = append(in, make([]byte, 8)...)
+	return &seededReader{in: in, off: len(seededReader{}.out), h: newKeccakHasher()}
+}
+
+// Read 实现io.Reader，永不返回错误
+func (s *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if s.off == len(s.out) {
+			binary.BigEndian.PutUint64(s.in[len(s.in)-8:], s.block)
+			s.block++
+			copy(s.out[:], s.h.sum(s.in))
+			s.off = 0
+		}
+		c := copy(p[n:], s.out[s.off:])
+		s.off += c
+		n += c
+	}
+	return n, nil
+}
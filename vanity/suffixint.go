@@ -0,0 +1,79 @@
+package vanity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// SuffixInt 把地址末尾Digits个十六进制字符当作整数匹配，如末尾4位能被1000整除，DivisibleBy与Equals至少指定一个
+type SuffixInt struct {
+	Digits      int     // 末尾的十六进制字符数，1到16
+	DivisibleBy uint64  // 非0时须能被它整除
+	Equals      *uint64 // 非nil时须等于该值
+}
+
+// validate 检查参数是否可能匹配
+func (s *SuffixInt) validate() error {
+	if s.Digits < 1 || s.Digits > 16 {
+		return fmt.Errorf("末尾整数的位数应在1到16之间，实际%d", s.Digits)
+	}
+	if s.DivisibleBy == 0 && s.Equals == nil {
+		return errors.New("末尾整数需要指定整除的值或相等的值")
+	}
+	if s.Equals != nil {
+		if s.Digits < 16 && *s.Equals >= 1<<(4*s.Digits) {
+			return fmt.Errorf("%d位十六进制最大为%d，不可能等于%d", s.Digits, uint64(1)<<(4*s.Digits)-1, *s.Equals)
+		}
+		if s.DivisibleBy != 0 && *s.Equals%s.DivisibleBy != 0 {
+			return fmt.Errorf("%d不能被%d整除，永远不会匹配", *s.Equals, s.DivisibleBy)
+		}
+	}
+	return nil
+}
+
+// difficulty 返回期望尝试次数：16^Digits除以满足条件的值的个数
+func (s *SuffixInt) difficulty() float64 {
+	space := math.Pow(16, float64(s.Digits))
+	if s.Equals != nil {
+		return space
+	}
+	// [0, 16^Digits) 中D的倍数有 (16^Digits-1)/D + 1 个，0也算
+	return space / math.Floor((space-1)/float64(s.DivisibleBy)+1)
+}
+
+// String 返回参数的简短描述，键名与命令行参数一致
+func (s *SuffixInt) String() string {
+	out := fmt.Sprintf("suffix-int=%d", s.Digits)
+	if s.DivisibleBy != 0 {
+		out += fmt.Sprintf(" divisible-by=%d", s.DivisibleBy)
+	}
+	if s.Equals != nil {
+		out += fmt.Sprintf(" equals=%d", *s.Equals)
+	}
+	return out
+}
+
+// match 检查末尾整数v是否满足条件
+func (s *SuffixInt) match(v uint64) bool {
+	if s.DivisibleBy != 0 && v%s.DivisibleBy != 0 {
+		return false
+	}
+	return s.Equals == nil || v == *s.Equals
+}
+
+// matchHex 检查小写十六进制地址（不含0x）末尾的整数是否满足条件
+func (s *SuffixInt) matchHex(target string) bool {
+	v, err := strconv.ParseUint(target[len(target)-s.Digits:], 16, 64)
+	return err == nil && s.match(v)
+}
+
+// suffixValue 直接从20字节地址取末尾digits个半字节组成的整数
+func suffixValue(a *[20]byte, digits int) uint64 {
+	var v uint64
+	for i := addressLen - digits; i < addressLen; i++ {
+		v = v<<4 | uint64(nibbleAt(a, i))
+	}
+	return v
+}
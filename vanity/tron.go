@@ -0,0 +1,61 @@
+package vanity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// tronPrefix Tron主网地址的版本字节，base58check编码后总是以T开头
+const tronPrefix = 0x41
+
+// base58Alphabet Bitcoin/Tron使用的base58字母表
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// TronAddress 将带0x前缀的以太坊地址转换为Tron的base58check地址（0x41 ++ 20字节 ++ 4字节校验和）
+// 输入不是40位十六进制时返回空字符串
+func TronAddress(address string) string {
+	var payload [1 + 20 + 4]byte
+	payload[0] = tronPrefix
+	body := strings.TrimPrefix(address, "0x")
+	if len(body) != 40 {
+		return ""
+	}
+	if _, err := hex.Decode(payload[1:21], []byte(body)); err != nil {
+		return ""
+	}
+	first := sha256.Sum256(payload[:21])
+	second := sha256.Sum256(first[:])
+	copy(payload[21:], second[:4])
+	return base58Encode(payload[:])
+}
+
+// base58Encode base58编码，前导零字节编码为'1'
+func base58Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	// 每字节约需log(256)/log(58)≈1.37个base58字符
+	digits := make([]byte, 0, len(b)*138/100+1)
+	for _, c := range b[zeros:] {
+		carry := int(c)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = base58Alphabet[d]
+	}
+	return string(out)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version 发布时可用 -ldflags "-X main.version=v1.2.3" 指定，未指定时取模块版本
+var version = ""
+
+// versionString 返回版本、提交、构建时间和Go版本，提交信息来自构建时嵌入的VCS信息
+func versionString() string {
+	v, commit, built, dirty := version, "unknown", "", false
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				commit = s.Value
+			case "vcs.time":
+				built = s.Value
+			case "vcs.modified":
+				dirty = s.Value == "true"
+			}
+		}
+	}
+	if v == "" {
+		v = "(devel)"
+	}
+	if dirty {
+		commit += "-dirty"
+	}
+	s := fmt.Sprintf("ethaddress %s commit %s", v, commit)
+	if built != "" {
+		s += " " + built
+	}
+	return fmt.Sprintf("%s %s %s/%s", s, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
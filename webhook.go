@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dmqf12/ethaddress/vanity"
+)
+
+// webhookAttempts 每个匹配最多POST的次数，失败后依次等待1s、2s…
+const webhookAttempts = 3
+
+// webhookQueueSize 等待发送的匹配数上限，队列满时send阻塞，搜索随之放慢
+const webhookQueueSize = 64
+
+// webhookRecord POST给webhook的JSON内容
+type webhookRecord struct {
+	Address         string `json:"address"`
+	ChecksumAddress string `json:"checksumAddress"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Salt            string `json:"salt,omitempty"`
+	Attempts        int64  `json:"attempts"`
+}
+
+// webhookSender 将每个匹配以JSON POST到指定URL，由后台协程按顺序发送，不阻塞调用方
+type webhookSender struct {
+	url    string
+	client *http.Client
+	queue  chan webhookRecord
+	done   chan struct{} // 后台协程发送完队列后关闭
+}
+
+// newWebhookSender 校验URL并创建发送器，insecure表示URL不是HTTPS，私钥将以明文传输
+func newWebhookSender(rawURL string) (s *webhookSender, insecure bool, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, false, errors.New("需要http(s)://开头的完整URL")
+	}
+	s = &webhookSender{
+		url:    rawURL,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan webhookRecord, webhookQueueSize),
+		done:   make(chan struct{}),
+	}
+	go s.loop()
+	return s, u.Scheme != "https", nil
+}
+
+// send 把匹配放入发送队列后立即返回，重试在后台进行，不占用onMatch的锁；队列满时等待
+func (s *webhookSender) send(m vanity.Match) {
+	s.queue <- webhookRecord{
+		Address:         m.Address,
+		ChecksumAddress: m.ChecksumAddress,
+		PrivateKey:      m.PrivateKey,
+		Salt:            m.Salt,
+		Attempts:        m.Attempts,
+	}
+}
+
+// close 停止接收新的匹配，等待队列中的匹配发送完（或重试用尽）
+func (s *webhookSender) close() {
+	close(s.queue)
+	<-s.done
+}
+
+// loop 依次发送队列中的匹配
+func (s *webhookSender) loop() {
+	defer close(s.done)
+	for rec := range s.queue {
+		s.deliver(rec)
+	}
+}
+
+// deliver POST一个匹配，失败时有限重试，最终失败只记录日志不影响搜索
+func (s *webhookSender) deliver(rec webhookRecord) {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		slog.Error("格式化webhook内容失败", "err", err)
+		return
+	}
+	for i := 0; i < webhookAttempts; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * time.Second)
+		}
+		if err = s.post(body); err == nil {
+			return
+		}
+	}
+	slog.Error("发送webhook失败", "address", rec.Address, "tries", webhookAttempts, "err", err)
+}
+
+// post 发送一次请求，非2xx状态码视为失败
+func (s *webhookSender) post(body []byte) error {
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
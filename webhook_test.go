@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookSendDoesNotBlock(t *testing.T) {
+	var mu sync.Mutex
+	var got []webhookRecord
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		var rec webhookRecord
+		json.NewDecoder(r.Body).Decode(&rec)
+		mu.Lock()
+		got = append(got, rec)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	s, _, err := newWebhookSender(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		m := testMatch
+		m.Attempts = int64(i)
+		s.send(m)
+	}
+	// 慢速的接收端不应拖慢调用方（onMatch持有生成器的锁）
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("send阻塞了 %s", elapsed)
+	}
+	s.close()
+	if len(got) != 3 {
+		t.Fatalf("close后收到 %d 个匹配，期望 3", len(got))
+	}
+	for i, rec := range got {
+		if rec.Attempts != int64(i) || rec.PrivateKey != testMatch.PrivateKey {
+			t.Errorf("第%d个匹配为 %+v", i+1, rec)
+		}
+	}
+}